@@ -0,0 +1,28 @@
+// Copyright (C) 2026 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+// Command errsvet runs the analyzers for the programs using github.com/sttk/errs.
+//
+// It can be run as a standalone command:
+//
+//	errsvet ./...
+//
+// or via go vet:
+//
+//	go vet -vettool=$(which errsvet) ./...
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/multichecker"
+
+	"github.com/sttk/errs/analysis/exhaustive"
+	"github.com/sttk/errs/analysis/okerror"
+)
+
+func main() {
+	multichecker.Main(
+		exhaustive.Analyzer,
+		okerror.Analyzer,
+	)
+}
@@ -0,0 +1,326 @@
+// Copyright (C) 2026 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+// Package exhaustive provides an analyzer which reports type switches on the reason of an
+// errs.Err which miss some of the reason types in a closed set.
+//
+// A closed set of reason types is declared with the directive comment //errs:reasons.
+//
+// On a type declaration, the directive declares the types in the declaration as a closed set of
+// the package. If the directive has arguments, the listed types are declared instead.
+//
+//	//errs:reasons
+//	type (
+//	    FailToRead struct{ Path string }
+//	    FailToWrite struct{ Path string }
+//	)
+//
+// A type switch on errs.Err.Reason() without a default case which has a case for any type in a
+// package's closed set must have cases for all types in that set. This applies in the declaring
+// package and in every package which imports it.
+//
+// On a function declaration, the directive declares a closed set for the type switches in the
+// function, and such type switches without a default case must have cases for all the listed
+// types. The types are written as Go type expressions which are valid in the file.
+//
+//	//errs:reasons FailToRead FailToWrite *os.PathError
+//	func handle(e errs.Err) { ... }
+//
+// A case for T or *T covers the type T in a closed set, and a case for an interface covers the
+// types which implement it.
+package exhaustive
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// Analyzer is the analyzer which reports non-exhaustive type switches on errs.Err.Reason().
+var Analyzer = &analysis.Analyzer{
+	Name:      "errsexhaustive",
+	Doc:       "report type switches on errs.Err.Reason() which miss reason types in a closed set",
+	Run:       run,
+	Requires:  []*analysis.Analyzer{inspect.Analyzer},
+	FactTypes: []analysis.Fact{new(reasonSetsFact)},
+}
+
+const (
+	errsPkgPath = "github.com/sttk/errs"
+	directive   = "//errs:reasons"
+)
+
+// reasonSetsFact is the package fact which holds the closed sets of reason types declared in a
+// package. Each type is represented by its qualified name, such as "example.com/foo.FailToRead".
+type reasonSetsFact struct {
+	Sets [][]string
+}
+
+func (*reasonSetsFact) AFact() {}
+
+func (f *reasonSetsFact) String() string {
+	sets := make([]string, len(f.Sets))
+	for i, set := range f.Sets {
+		sets[i] = "{" + strings.Join(set, ", ") + "}"
+	}
+	return "reasonSets(" + strings.Join(sets, " ") + ")"
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	var pkgSets [][]string
+	for _, file := range pass.Files {
+		for _, decl := range file.Decls {
+			if gd, ok := decl.(*ast.GenDecl); ok && gd.Tok == token.TYPE {
+				if set, ok := typeDeclSet(pass, gd); ok {
+					pkgSets = append(pkgSets, set)
+				}
+			}
+		}
+	}
+	if len(pkgSets) > 0 {
+		pass.ExportPackageFact(&reasonSetsFact{Sets: pkgSets})
+	}
+
+	sets := append([][]string(nil), pkgSets...)
+	for _, f := range pass.AllPackageFacts() {
+		if f.Package != pass.Pkg {
+			sets = append(sets, f.Fact.(*reasonSetsFact).Sets...)
+		}
+	}
+
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	filter := []ast.Node{(*ast.FuncDecl)(nil), (*ast.TypeSwitchStmt)(nil)}
+
+	// A type switch uses the set declared on the nearest enclosing function declaration, if any.
+	// A type switch in a package-level function literal has no enclosing function declaration.
+	funcSets := make(map[*ast.FuncDecl][]string)
+	insp.WithStack(filter, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+		switch n := n.(type) {
+		case *ast.FuncDecl:
+			funcSets[n] = funcDeclSet(pass, n)
+		case *ast.TypeSwitchStmt:
+			checkSwitch(pass, n, enclosingFuncSet(stack, funcSets), sets)
+		}
+		return true
+	})
+
+	return nil, nil
+}
+
+// enclosingFuncSet returns the set declared on the nearest function declaration in the stack.
+func enclosingFuncSet(stack []ast.Node, funcSets map[*ast.FuncDecl][]string) []string {
+	for i := len(stack) - 1; i >= 0; i-- {
+		if fd, ok := stack[i].(*ast.FuncDecl); ok {
+			return funcSets[fd]
+		}
+	}
+	return nil
+}
+
+// directiveArgs returns the arguments of the directive in the comment group.
+func directiveArgs(doc *ast.CommentGroup) ([]string, bool) {
+	if doc == nil {
+		return nil, false
+	}
+	for _, c := range doc.List {
+		if c.Text == directive || strings.HasPrefix(c.Text, directive+" ") {
+			return strings.Fields(strings.TrimPrefix(c.Text, directive)), true
+		}
+	}
+	return nil, false
+}
+
+func typeDeclSet(pass *analysis.Pass, gd *ast.GenDecl) ([]string, bool) {
+	args, ok := directiveArgs(gd.Doc)
+	if !ok {
+		return nil, false
+	}
+	if len(args) > 0 {
+		return resolveTypes(pass, gd.Specs[0].Pos(), args), true
+	}
+
+	var set []string
+	for _, spec := range gd.Specs {
+		ts := spec.(*ast.TypeSpec)
+		if obj := pass.TypesInfo.Defs[ts.Name]; obj != nil {
+			set = append(set, typeName(obj.Type()))
+		}
+	}
+	return set, len(set) > 0
+}
+
+func funcDeclSet(pass *analysis.Pass, fd *ast.FuncDecl) []string {
+	args, ok := directiveArgs(fd.Doc)
+	if !ok {
+		return nil
+	}
+	if len(args) == 0 {
+		pass.Reportf(fd.Name.Pos(), "%s on a function requires reason types", directive)
+		return nil
+	}
+	return resolveTypes(pass, fd.Name.Pos(), args)
+}
+
+func resolveTypes(pass *analysis.Pass, pos token.Pos, exprs []string) []string {
+	var set []string
+	for _, expr := range exprs {
+		tv, err := types.Eval(pass.Fset, pass.Pkg, pos, expr)
+		if err != nil || !tv.IsType() {
+			pass.Reportf(pos, "%s: unknown reason type %s", directive, expr)
+			continue
+		}
+		set = append(set, typeName(tv.Type))
+	}
+	return set
+}
+
+// typeName returns the qualified name of the type, dereferencing a pointer type and resolving
+// aliases.
+func typeName(t types.Type) string {
+	t = types.Unalias(t)
+	if p, ok := t.(*types.Pointer); ok {
+		t = types.Unalias(p.Elem())
+	}
+	return types.TypeString(t, nil)
+}
+
+func checkSwitch(pass *analysis.Pass, ts *ast.TypeSwitchStmt, funcSet []string, sets [][]string) {
+	if !isReasonSwitch(pass, ts) {
+		return
+	}
+
+	var caseTypes []types.Type
+	for _, stmt := range ts.Body.List {
+		cc := stmt.(*ast.CaseClause)
+		if cc.List == nil { // default
+			return
+		}
+		for _, expr := range cc.List {
+			if t := pass.TypesInfo.TypeOf(expr); t != nil {
+				caseTypes = append(caseTypes, t)
+			}
+		}
+	}
+
+	covered := map[string]bool{}
+	var ifaces []*types.Interface
+	for _, t := range caseTypes {
+		if iface, ok := t.Underlying().(*types.Interface); ok {
+			ifaces = append(ifaces, iface)
+			continue
+		}
+		covered[typeName(t)] = true
+	}
+
+	isCovered := func(name string) bool {
+		return covered[name] || implementsAny(pass, name, ifaces)
+	}
+
+	var required []string
+	if funcSet != nil {
+		required = funcSet
+	} else {
+		for _, set := range sets {
+			for _, name := range set {
+				if isCovered(name) {
+					required = append(required, set...)
+					break
+				}
+			}
+		}
+	}
+
+	missing := map[string]bool{}
+	for _, name := range required {
+		if !isCovered(name) {
+			missing[name] = true
+		}
+	}
+	if len(missing) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(missing))
+	for name := range missing {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	pass.Reportf(ts.Pos(), "missing cases in type switch on errs.Err.Reason(): %s",
+		strings.Join(names, ", "))
+}
+
+func isReasonSwitch(pass *analysis.Pass, ts *ast.TypeSwitchStmt) bool {
+	var x ast.Expr
+	switch s := ts.Assign.(type) {
+	case *ast.AssignStmt:
+		x = s.Rhs[0]
+	case *ast.ExprStmt:
+		x = s.X
+	}
+	ta, ok := x.(*ast.TypeAssertExpr)
+	if !ok {
+		return false
+	}
+	call, ok := ast.Unparen(ta.X).(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Reason" {
+		return false
+	}
+	fn, ok := pass.TypesInfo.Uses[sel.Sel].(*types.Func)
+	if !ok {
+		return false
+	}
+	recv := fn.Type().(*types.Signature).Recv()
+	if recv == nil {
+		return false
+	}
+	return typeName(recv.Type()) == errsPkgPath+".Err"
+}
+
+// implementsAny reports whether the named type implements any of the interfaces.
+func implementsAny(pass *analysis.Pass, name string, ifaces []*types.Interface) bool {
+	if len(ifaces) == 0 {
+		return false
+	}
+	t := lookupType(pass, name)
+	if t == nil {
+		return false
+	}
+	for _, iface := range ifaces {
+		if types.Implements(t, iface) || types.Implements(types.NewPointer(t), iface) {
+			return true
+		}
+	}
+	return false
+}
+
+func lookupType(pass *analysis.Pass, name string) types.Type {
+	i := strings.LastIndex(name, ".")
+	if i < 0 {
+		return nil
+	}
+	path, local := name[:i], name[i+1:]
+
+	pkgs := append([]*types.Package{pass.Pkg}, pass.Pkg.Imports()...)
+	for _, pkg := range pkgs {
+		if pkg.Path() == path {
+			if obj, ok := pkg.Scope().Lookup(local).(*types.TypeName); ok {
+				return obj.Type()
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,13 @@
+package exhaustive_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/sttk/errs/analysis/exhaustive"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), exhaustive.Analyzer, "reasons", "a")
+}
@@ -0,0 +1,54 @@
+package a
+
+import (
+	"os"
+
+	"github.com/sttk/errs"
+
+	"reasons"
+)
+
+type InvalidValue struct{}
+
+func imported(e errs.Err) {
+	switch e.Reason().(type) { // want `missing cases in type switch on errs.Err.Reason\(\): reasons.FailToClose, reasons.FailToWrite`
+	case reasons.FailToRead:
+	}
+}
+
+//errs:reasons InvalidValue *os.PathError reasons.Other
+func local(e errs.Err) {
+	switch e.Reason().(type) { // want `missing cases in type switch on errs.Err.Reason\(\): io/fs.PathError`
+	case InvalidValue:
+	case reasons.Other:
+	}
+
+	switch e.Reason().(type) {
+	case *InvalidValue, *os.PathError, reasons.Other:
+	}
+}
+
+// A package-level function literal does not inherit the set of the preceding function.
+var literal = func(e errs.Err) {
+	switch e.Reason().(type) {
+	case InvalidValue:
+	}
+}
+
+//errs:reasons InvalidValue reasons.Other
+func withLiteral(e errs.Err) {
+	f := func(e errs.Err) {
+		switch e.Reason().(type) { // want `missing cases in type switch on errs.Err.Reason\(\): reasons.Other`
+		case InvalidValue:
+		}
+	}
+	f(e)
+}
+
+//errs:reasons
+func noTypes(e errs.Err) { // want `//errs:reasons on a function requires reason types`
+}
+
+//errs:reasons Unknown
+func unknownType(e errs.Err) { // want `//errs:reasons: unknown reason type Unknown`
+}
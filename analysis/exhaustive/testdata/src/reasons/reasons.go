@@ -0,0 +1,62 @@
+package reasons // want package:`reasonSets\({reasons.FailToRead, reasons.FailToWrite, reasons.FailToClose}\)`
+
+import (
+	"github.com/sttk/errs"
+)
+
+//errs:reasons
+type (
+	FailToRead  struct{ Path string }
+	FailToWrite struct{ Path string }
+	FailToClose struct{ Path string }
+)
+
+type Other struct{}
+
+type Describer interface {
+	Describe() string
+}
+
+func (r FailToRead) Describe() string  { return "read" }
+func (r FailToWrite) Describe() string { return "write" }
+
+func covered(e errs.Err) {
+	switch e.Reason().(type) {
+	case FailToRead, *FailToWrite:
+	case FailToClose:
+	}
+}
+
+func missing(e errs.Err) {
+	switch r := e.Reason().(type) { // want `missing cases in type switch on errs.Err.Reason\(\): reasons.FailToClose`
+	case FailToRead:
+		_ = r.Path
+	case FailToWrite:
+	}
+}
+
+func withDefault(e errs.Err) {
+	switch e.Reason().(type) {
+	case FailToRead:
+	default:
+	}
+}
+
+func unrelated(e errs.Err) {
+	switch e.Reason().(type) {
+	case Other:
+	case nil:
+	}
+}
+
+func byInterface(e errs.Err) {
+	switch e.Reason().(type) { // want `missing cases in type switch on errs.Err.Reason\(\): reasons.FailToClose`
+	case Describer:
+	}
+}
+
+func notReason(v any) {
+	switch v.(type) {
+	case FailToRead:
+	}
+}
@@ -0,0 +1,239 @@
+// Copyright (C) 2026 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+// Package okerror provides an analyzer which reports errs.Err values converted to error.
+//
+// Since errs.Err is a struct, an errs.Err converted to error is not nil even if it indicates no
+// error, so a function returning errs.Ok() as error makes the check `if err != nil` of its callers
+// true on success:
+//
+//	func doSomething() error {
+//	    ...
+//	    return errs.Ok() // reported
+//	}
+//
+// This analyzer reports errs.Err values which are returned as error, assigned to variables of
+// the type error, or used as initial values of such variables, and suggests the fix which calls
+// the method errs.Err.AsError, which returns nil if the errs.Err indicates no error.
+//
+// A call of errs.New or errs.NewSkip whose reason is a composite literal, a pointer to it, or a
+// constant is not reported, because the errs.Err it creates never indicates no error:
+//
+//	func doSomething() error {
+//	    ...
+//	    return errs.New(FailToDoSomething{}) // not reported
+//	}
+package okerror
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// Analyzer is the analyzer which reports errs.Err values converted to error.
+var Analyzer = &analysis.Analyzer{
+	Name:     "errsokerror",
+	Doc:      "report errs.Err values converted to error, which are not nil even if they are ok",
+	Run:      run,
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+}
+
+const errsPkgPath = "github.com/sttk/errs"
+
+var errorType = types.Universe.Lookup("error").Type()
+
+func run(pass *analysis.Pass) (any, error) {
+	if pass.Pkg.Path() == errsPkgPath {
+		return nil, nil
+	}
+
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	filter := []ast.Node{
+		(*ast.ReturnStmt)(nil),
+		(*ast.AssignStmt)(nil),
+		(*ast.ValueSpec)(nil),
+	}
+
+	insp.WithStack(filter, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+		switch n := n.(type) {
+		case *ast.ReturnStmt:
+			checkReturn(pass, n, stack)
+		case *ast.AssignStmt:
+			checkAssign(pass, n)
+		case *ast.ValueSpec:
+			checkValueSpec(pass, n)
+		}
+		return true
+	})
+
+	return nil, nil
+}
+
+func checkReturn(pass *analysis.Pass, ret *ast.ReturnStmt, stack []ast.Node) {
+	sig := enclosingSignature(pass, stack)
+	if sig == nil || sig.Results().Len() != len(ret.Results) {
+		return
+	}
+	for i, expr := range ret.Results {
+		check(pass, expr, sig.Results().At(i).Type())
+	}
+}
+
+func enclosingSignature(pass *analysis.Pass, stack []ast.Node) *types.Signature {
+	for i := len(stack) - 1; i >= 0; i-- {
+		switch f := stack[i].(type) {
+		case *ast.FuncLit:
+			sig, _ := pass.TypesInfo.TypeOf(f).(*types.Signature)
+			return sig
+		case *ast.FuncDecl:
+			if obj, ok := pass.TypesInfo.Defs[f.Name].(*types.Func); ok {
+				return obj.Type().(*types.Signature)
+			}
+			return nil
+		}
+	}
+	return nil
+}
+
+func checkAssign(pass *analysis.Pass, as *ast.AssignStmt) {
+	if as.Tok != token.ASSIGN || len(as.Lhs) != len(as.Rhs) {
+		return
+	}
+	for i, lhs := range as.Lhs {
+		if id, ok := lhs.(*ast.Ident); ok && id.Name == "_" {
+			continue
+		}
+		check(pass, as.Rhs[i], pass.TypesInfo.TypeOf(lhs))
+	}
+}
+
+func checkValueSpec(pass *analysis.Pass, vs *ast.ValueSpec) {
+	if vs.Type == nil || len(vs.Names) != len(vs.Values) {
+		return
+	}
+	t := pass.TypesInfo.TypeOf(vs.Type)
+	for _, v := range vs.Values {
+		check(pass, v, t)
+	}
+}
+
+func check(pass *analysis.Pass, expr ast.Expr, dst types.Type) {
+	if dst == nil || !types.Identical(dst, errorType) || !isErr(pass.TypesInfo.TypeOf(expr)) {
+		return
+	}
+	if isNewCallWithReason(pass, expr) {
+		return
+	}
+
+	msg := "errs.Err converted to error is not nil even if it is ok; use AsError()"
+	if isOkCall(pass, expr) {
+		msg = "errs.Ok() converted to error is not nil; use AsError() or nil"
+	}
+
+	var edits []analysis.TextEdit
+	if isPrimary(expr) {
+		edits = []analysis.TextEdit{
+			{Pos: expr.End(), End: expr.End(), NewText: []byte(".AsError()")},
+		}
+	} else {
+		edits = []analysis.TextEdit{
+			{Pos: expr.Pos(), End: expr.Pos(), NewText: []byte("(")},
+			{Pos: expr.End(), End: expr.End(), NewText: []byte(").AsError()")},
+		}
+	}
+
+	pass.Report(analysis.Diagnostic{
+		Pos:     expr.Pos(),
+		End:     expr.End(),
+		Message: msg,
+		SuggestedFixes: []analysis.SuggestedFix{
+			{Message: "Call AsError()", TextEdits: edits},
+		},
+	})
+}
+
+func isErr(t types.Type) bool {
+	if t == nil {
+		return false
+	}
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == errsPkgPath && obj.Name() == "Err"
+}
+
+func isOkCall(pass *analysis.Pass, expr ast.Expr) bool {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	return ok && errsFuncName(pass, call) == "Ok"
+}
+
+// isNewCallWithReason reports whether the expression is a call of errs.New or errs.NewSkip whose
+// reason is obviously not nil: a composite literal, a pointer to it, or a constant.
+func isNewCallWithReason(pass *analysis.Pass, expr ast.Expr) bool {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	var reason ast.Expr
+	switch errsFuncName(pass, call) {
+	case "New":
+		if len(call.Args) > 0 {
+			reason = call.Args[0]
+		}
+	case "NewSkip":
+		if len(call.Args) > 1 {
+			reason = call.Args[1]
+		}
+	}
+	if reason == nil {
+		return false
+	}
+	reason = ast.Unparen(reason)
+	if u, ok := reason.(*ast.UnaryExpr); ok && u.Op == token.AND {
+		reason = ast.Unparen(u.X)
+	}
+	if _, ok := reason.(*ast.CompositeLit); ok {
+		return true
+	}
+	return pass.TypesInfo.Types[reason].Value != nil
+}
+
+// errsFuncName returns the name of the function of the errs package which is called, or "" if
+// the called function is not of the errs package.
+func errsFuncName(pass *analysis.Pass, call *ast.CallExpr) string {
+	var id *ast.Ident
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		id = fun
+	case *ast.SelectorExpr:
+		id = fun.Sel
+	default:
+		return ""
+	}
+	fn, ok := pass.TypesInfo.Uses[id].(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != errsPkgPath {
+		return ""
+	}
+	return fn.Name()
+}
+
+// isPrimary reports whether a method call can be appended to the expression without parentheses.
+func isPrimary(expr ast.Expr) bool {
+	switch expr.(type) {
+	case *ast.Ident, *ast.CallExpr, *ast.SelectorExpr, *ast.IndexExpr, *ast.IndexListExpr,
+		*ast.ParenExpr, *ast.CompositeLit, *ast.TypeAssertExpr:
+		return true
+	}
+	return false
+}
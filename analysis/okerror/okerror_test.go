@@ -0,0 +1,13 @@
+package okerror_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/sttk/errs/analysis/okerror"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), okerror.Analyzer, "b")
+}
@@ -0,0 +1,58 @@
+package b
+
+import (
+	"github.com/sttk/errs"
+)
+
+type FailToDoSomething struct{}
+
+func returnOk() error {
+	return errs.Ok() // want `errs.Ok\(\) converted to error is not nil; use AsError\(\) or nil`
+}
+
+func returnErr(fail bool) (int, error) {
+	e := errs.Ok()
+	if fail {
+		e = errs.New(FailToDoSomething{})
+	}
+	return 0, e // want `errs.Err converted to error is not nil even if it is ok; use AsError\(\)`
+}
+
+func returnPointer(p *errs.Err) error {
+	return *p // want `errs.Err converted to error is not nil even if it is ok; use AsError\(\)`
+}
+
+func returnInFuncLit() {
+	f := func() error {
+		return errs.Ok() // want `errs.Ok\(\) converted to error is not nil; use AsError\(\) or nil`
+	}
+	_ = f
+}
+
+func assign() {
+	var err error
+	err = errs.Ok() // want `errs.Ok\(\) converted to error is not nil; use AsError\(\) or nil`
+	_ = err
+
+	var err2 error = errs.New(FailToDoSomething{})
+	_ = err2
+
+	err = errs.NewSkip(1, &FailToDoSomething{})
+	err = errs.New("failed", err2)
+	_ = err
+}
+
+func newWithVariable(reason any) error {
+	return errs.New(reason) // want `errs.Err converted to error is not nil even if it is ok; use AsError\(\)`
+}
+
+func notReported() (errs.Err, error) {
+	e := errs.Ok()
+	var a any = e
+	_ = a
+	_ = e
+	if e.IsOk() {
+		return e, nil
+	}
+	return e, e.AsError()
+}
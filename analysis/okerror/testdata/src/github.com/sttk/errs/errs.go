@@ -0,0 +1,36 @@
+package errs
+
+type Err struct {
+	reason any
+}
+
+func New(reason any, cause ...error) Err {
+	return Err{reason: reason}
+}
+
+func NewSkip(skip int, reason any, cause ...error) Err {
+	return Err{reason: reason}
+}
+
+func Ok() Err {
+	return Err{}
+}
+
+func (e Err) Reason() any {
+	return e.reason
+}
+
+func (e Err) Error() string {
+	return "err"
+}
+
+func (e Err) IsOk() bool {
+	return e.reason == nil
+}
+
+func (e Err) AsError() error {
+	if e.IsOk() {
+		return nil
+	}
+	return e
+}
@@ -0,0 +1,194 @@
+// Copyright (C) 2025-2026 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package errs
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// BatchItem is the struct which holds the Err of an item which failed in a bulk operation.
+// An item is keyed by its index or its ID. Index is -1 if the item is keyed by an ID.
+type BatchItem struct {
+	Index int
+	ID    string
+	Err   Err
+}
+
+// Key returns the string which identifies this item: the ID if the item is keyed by an ID,
+// otherwise the index.
+func (it BatchItem) Key() string {
+	if it.Index < 0 {
+		return it.ID
+	}
+	return fmt.Sprint(it.Index)
+}
+
+// BatchErr is the struct which records the failures of the items in a bulk operation, such as
+// inserting many rows or sending many messages, in which some items can fail while the rest
+// succeed.
+//
+// A BatchErr is created with NewBatchErr, and records the Err of each failed item with Add or
+// AddID. A BatchErr is safe for concurrent use.
+// Like Err, a BatchErr can be formatted with fmt, marshalled to and unmarshalled from JSON, and
+// logged with log/slog.
+type BatchErr struct {
+	mu    sync.Mutex
+	total int
+	items []BatchItem
+}
+
+// NewBatchErr creates a BatchErr for a bulk operation of the specified number of items.
+func NewBatchErr(total int) *BatchErr {
+	return &BatchErr{total: total}
+}
+
+// Add records the Err of the item at the specified index, and returns this BatchErr.
+// If the Err indicates no error, this method records nothing.
+// This method panics if the index is negative, because a negative index is used to mark an item
+// keyed by an ID. (Use AddID for such an item.)
+func (b *BatchErr) Add(index int, e Err) *BatchErr {
+	if index < 0 {
+		panic(fmt.Sprintf("errs: negative index of a batch item: %d", index))
+	}
+	if e.IsOk() {
+		return b
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.items = append(b.items, BatchItem{Index: index, Err: e})
+	return b
+}
+
+// AddID records the Err of the item with the specified ID, and returns this BatchErr.
+// If the Err indicates no error, this method records nothing.
+func (b *BatchErr) AddID(id string, e Err) *BatchErr {
+	if e.IsOk() {
+		return b
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.items = append(b.items, BatchItem{Index: -1, ID: id, Err: e})
+	return b
+}
+
+// Total returns the number of the items in the bulk operation.
+// If more items than this number have been recorded, this returns the number of the recorded
+// items.
+func (b *BatchErr) Total() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.totalLocked()
+}
+
+func (b *BatchErr) totalLocked() int {
+	if len(b.items) > b.total {
+		return len(b.items)
+	}
+	return b.total
+}
+
+// Len returns the number of the recorded failed items.
+func (b *BatchErr) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.items)
+}
+
+// Items returns the recorded failed items in the order in which they were recorded.
+func (b *BatchErr) Items() []BatchItem {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	items := make([]BatchItem, len(b.items))
+	copy(items, b.items)
+	return items
+}
+
+// IsOk returns true if no failed item is recorded.
+func (b *BatchErr) IsOk() bool {
+	return b.Len() == 0
+}
+
+// IsNotOk returns true if some failed items are recorded.
+func (b *BatchErr) IsNotOk() bool {
+	return b.Len() != 0
+}
+
+// AsError returns nil if no failed item is recorded, and otherwise returns this BatchErr as an
+// error.
+func (b *BatchErr) AsError() error {
+	if b.IsOk() {
+		return nil
+	}
+	return b
+}
+
+// CountsByReason returns the numbers of the failed items per reason type.
+// The keys are the fully qualified type names of the reasons, which are the same as the
+// "reason_type" values in JSON, so the counts are the same after a round trip through JSON even if
+// the reason types are not registered.
+// A pointer reason is counted as its element type, like the reason handlers and ReasonOf treat a
+// pointer reason and a value reason as the same type.
+func (b *BatchErr) CountsByReason() map[string]int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.countsByReasonLocked()
+}
+
+func (b *BatchErr) countsByReasonLocked() map[string]int {
+	counts := make(map[string]int)
+	for _, it := range b.items {
+		counts[reasonTypeNameOf(it.Err.reason)]++
+	}
+	return counts
+}
+
+func reasonTypeNameOf(reason any) string {
+	if r, ok := reason.(UnknownReason); ok {
+		return strings.TrimPrefix(r.Type, "*")
+	}
+	return reasonTypeName(reasonKey(reflect.TypeOf(reason)))
+}
+
+// SuccessRatio returns the ratio of the succeeded items to all the items, which is between 0
+// and 1. If the total number of the items is zero, this returns 1.
+func (b *BatchErr) SuccessRatio() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	total := b.totalLocked()
+	if total == 0 {
+		return 1
+	}
+	return float64(total-len(b.items)) / float64(total)
+}
+
+// Error returns a compact summary of this BatchErr, which consists of the numbers of the failed
+// items and all the items, and the numbers of the failed items per reason type.
+func (b *BatchErr) Error() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	counts := b.countsByReasonLocked()
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "github.com/sttk/errs.BatchErr {failed:%d total:%d reasons:{",
+		len(b.items), b.totalLocked())
+	for i, name := range names {
+		if i > 0 {
+			sb.WriteString(" ")
+		}
+		fmt.Fprintf(&sb, "%s:%d", name, counts[name])
+	}
+	sb.WriteString("}}")
+	return sb.String()
+}
@@ -0,0 +1,141 @@
+package errs_test
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/errs"
+)
+
+func TestBatchErr(t *testing.T) {
+	t.Run("no failed item", func(t *testing.T) {
+		b := errs.NewBatchErr(3)
+		b.Add(0, errs.Ok()).AddID("abc", errs.Ok())
+		assert.True(t, b.IsOk())
+		assert.False(t, b.IsNotOk())
+		assert.Nil(t, b.AsError())
+		assert.Equal(t, b.Len(), 0)
+		assert.Equal(t, b.Total(), 3)
+		assert.Len(t, b.Items(), 0)
+		assert.Equal(t, b.CountsByReason(), map[string]int{})
+		assert.Equal(t, b.SuccessRatio(), 1.0)
+		assert.Equal(t, b.Error(), "github.com/sttk/errs.BatchErr {failed:0 total:3 reasons:{}}")
+	})
+
+	t.Run("failed items", func(t *testing.T) {
+		e0 := errs.New(InvalidValue{Name: "a"})
+		e1 := errs.New(FailToGetValue{Name: "b"})
+		e2 := errs.New(&InvalidValue{Name: "c"})
+
+		b := errs.NewBatchErr(8)
+		b.Add(1, e0).AddID("x", e1).Add(5, e2)
+
+		assert.True(t, b.IsNotOk())
+		assert.Equal(t, b.AsError(), error(b))
+		assert.Equal(t, b.Len(), 3)
+		assert.Equal(t, b.Items(), []errs.BatchItem{
+			{Index: 1, Err: e0},
+			{Index: -1, ID: "x", Err: e1},
+			{Index: 5, Err: e2},
+		})
+		assert.Equal(t, b.Items()[0].Key(), "1")
+		assert.Equal(t, b.Items()[1].Key(), "x")
+		assert.Equal(t, b.CountsByReason(), map[string]int{
+			"github.com/sttk/errs_test.InvalidValue":   2,
+			"github.com/sttk/errs_test.FailToGetValue": 1,
+		})
+		assert.Equal(t, b.SuccessRatio(), 5.0/8.0)
+		assert.Equal(t, b.Error(), "github.com/sttk/errs.BatchErr {failed:3 total:8 reasons:{github.com/sttk/errs_test.FailToGetValue:1 github.com/sttk/errs_test.InvalidValue:2}}")
+	})
+
+	t.Run("more items than total", func(t *testing.T) {
+		b := errs.NewBatchErr(1)
+		b.Add(0, errs.New("a")).Add(1, errs.New("a"))
+		assert.Equal(t, b.Total(), 2)
+		assert.Equal(t, b.SuccessRatio(), 0.0)
+		assert.Equal(t, b.CountsByReason(), map[string]int{"string": 2})
+	})
+
+	t.Run("negative index", func(t *testing.T) {
+		b := errs.NewBatchErr(1)
+		assert.PanicsWithValue(t, "errs: negative index of a batch item: -1", func() {
+			b.Add(-1, errs.New("a"))
+		})
+		assert.Equal(t, b.Len(), 0)
+	})
+
+	t.Run("zero total", func(t *testing.T) {
+		b := errs.NewBatchErr(0)
+		assert.Equal(t, b.SuccessRatio(), 1.0)
+	})
+
+	t.Run("errors.Is and errors.As", func(t *testing.T) {
+		cause := errors.New("def")
+		b := errs.NewBatchErr(2).Add(0, errs.New(InvalidValue{Name: "a"}, cause))
+		assert.True(t, errors.Is(b, cause))
+		assert.True(t, errs.HasReason[InvalidValue](b))
+
+		var e errs.Err
+		assert.True(t, errors.As(b, &e))
+		assert.Equal(t, e.Reason(), InvalidValue{Name: "a"})
+	})
+
+	t.Run("concurrent use", func(t *testing.T) {
+		b := errs.NewBatchErr(100)
+		var wg sync.WaitGroup
+		for i := 0; i < 100; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				if i%4 == 0 {
+					b.Add(i, errs.New("a"))
+				} else {
+					b.Add(i, errs.Ok())
+				}
+			}(i)
+		}
+		wg.Wait()
+		assert.Equal(t, b.Len(), 25)
+		assert.Equal(t, b.SuccessRatio(), 0.75)
+	})
+}
+
+func TestBatchErr_Format(t *testing.T) {
+	e0 := errs.New(InvalidValue{Name: "a"}, errors.New("def"))
+	e1 := errs.New("abc")
+	b := errs.NewBatchErr(5).Add(2, e0).AddID("x", e1)
+
+	t.Run("%v", func(t *testing.T) {
+		assert.Equal(t, fmt.Sprintf("%v", b), "2 of 5 items failed: [2: github.com/sttk/errs_test.InvalidValue{Name:a Value:}: def, x: abc]")
+		assert.Equal(t, fmt.Sprintf("%v", errs.NewBatchErr(5)), "0 of 5 items failed")
+	})
+
+	t.Run("%s and %q", func(t *testing.T) {
+		assert.Equal(t, fmt.Sprintf("%s", b), b.Error())
+		assert.Equal(t, fmt.Sprintf("%q", b), fmt.Sprintf("%q", b.Error()))
+	})
+
+	t.Run("%+v", func(t *testing.T) {
+		assert.Equal(t, fmt.Sprintf("%+v", b), `github.com/sttk/errs.BatchErr
+    failed: 2
+    total: 5
+    item 2: github.com/sttk/errs_test.InvalidValue
+        Name: a
+        Value: 
+        at batch_test.go:107
+        cause: def
+    item x: abc
+        at batch_test.go:108`)
+	})
+
+	t.Run("%#v", func(t *testing.T) {
+		assert.Equal(t, fmt.Sprintf("%#v", b), `&errs.BatchErr{total:5, items:[]errs.BatchItem{{Index:2, ID:"", Err:errs.Err{reason:errs_test.InvalidValue{Name:"a", Value:""}, file:"batch_test.go", line:107, cause:&errors.errorString{s:"def"}}}, {Index:-1, ID:"x", Err:errs.Err{reason:"abc", file:"batch_test.go", line:108}}}}`)
+	})
+
+	t.Run("unsupported verb", func(t *testing.T) {
+		assert.Equal(t, fmt.Sprintf("%d", errs.NewBatchErr(1)), "%!d(*errs.BatchErr=github.com/sttk/errs.BatchErr {failed:0 total:1 reasons:{}})")
+	})
+}
@@ -0,0 +1,373 @@
+// Copyright (C) 2026 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+// Package breaker provides a circuit breaker which stops calling a failing dependency, counting
+// the outcomes of the calls as errs.Err values.
+//
+// # Counting outcomes
+//
+// A Breaker counts successes and failures per reason type within a sliding window.
+// An errs.Err which indicates no error is a success, and an errs.Err with a reason is a failure
+// unless Config.IsFailure returns false for it. When the ratio of the failures to all the outcomes
+// in the window exceeds Config.FailureRatio, the breaker opens.
+//
+// While the breaker is open, Allow and Do return an errs.Err whose reason is CircuitOpen without
+// calling the dependency. After Config.OpenTimeout, the breaker becomes half-open and allows trial
+// calls. If they succeed, the breaker closes, and otherwise opens again.
+//
+//	b := breaker.New("inventory", breaker.Config{FailureRatio: 0.5})
+//
+//	e := b.Do(func() errs.Err {
+//	    return callInventoryService()
+//	})
+//
+// # Feeding from error handlers
+//
+// HandleErr has the signature of an error handler of the errs package, so a Breaker can count the
+// failures notified to the handlers. Since only failures are notified, the successes should be
+// recorded with Record. Alternatively, without recording successes, the breaker opens when the
+// number of failures in the window reaches Config.MinRequests.
+//
+//	errs.AddSyncReasonHandler(func(r Timeout, e errs.Err, tm time.Time) {
+//	    b.HandleErr(e, tm)
+//	})
+package breaker
+
+import (
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/sttk/errs"
+)
+
+// CircuitOpen is the reason of an errs.Err which a Breaker returns while it is open.
+// Name is the name of the breaker.
+type CircuitOpen struct {
+	Name string
+}
+
+func init() {
+	errs.RegisterReason[CircuitOpen]()
+}
+
+// State is the type of the states of a Breaker.
+type State int
+
+const (
+	// Closed is the state in which calls are allowed and their outcomes are counted.
+	Closed State = iota
+
+	// Open is the state in which calls are rejected.
+	Open
+
+	// HalfOpen is the state in which a limited number of trial calls are allowed.
+	HalfOpen
+)
+
+// String returns the name of the state.
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// Config is the struct which configures a Breaker.
+// If a field is zero, the default value is used.
+type Config struct {
+	// Window is the length of the sliding window in which outcomes are counted.
+	// The default is 10 seconds.
+	Window time.Duration
+
+	// Buckets is the number of the buckets into which the window is divided. Outcomes expire from
+	// the window per bucket. The default is 10.
+	Buckets int
+
+	// FailureRatio is the ratio of the failures to all the outcomes in the window, which opens the
+	// breaker when exceeded. The default is 0.5.
+	FailureRatio float64
+
+	// MinRequests is the minimum number of outcomes in the window before the ratio is evaluated.
+	// The default is 10.
+	MinRequests int
+
+	// OpenTimeout is the time for which the breaker stays open before it becomes half-open.
+	// The default is 30 seconds.
+	OpenTimeout time.Duration
+
+	// HalfOpenMaxCalls is the number of trial calls allowed while the breaker is half-open.
+	// The default is 1.
+	HalfOpenMaxCalls int
+
+	// IsFailure reports whether an errs.Err with a reason is counted as a failure.
+	// An errs.Err for which this returns false is counted as a success. This is useful for
+	// excluding the errors which are not caused by the dependency, such as invalid arguments.
+	// The default counts every errs.Err with a reason as a failure.
+	IsFailure func(errs.Err) bool
+
+	// Now returns the current time. This is for testing, and the default is time.Now.
+	Now func() time.Time
+}
+
+// Counts is the struct which holds the numbers of the outcomes in the window of a Breaker.
+type Counts struct {
+	// Successes is the number of the successes.
+	Successes int
+
+	// Failures is the numbers of the failures per reason type. A pointer reason type is counted as
+	// its pointed type.
+	Failures map[reflect.Type]int
+}
+
+// Total returns the number of all the outcomes.
+func (c Counts) Total() int {
+	n := c.Successes
+	for _, f := range c.Failures {
+		n += f
+	}
+	return n
+}
+
+// TotalFailures returns the number of the failures of all reason types.
+func (c Counts) TotalFailures() int {
+	var n int
+	for _, f := range c.Failures {
+		n += f
+	}
+	return n
+}
+
+type bucket struct {
+	epoch     int64
+	successes int
+	failures  map[reflect.Type]int
+}
+
+// Breaker is a circuit breaker. A Breaker is safe for concurrent use.
+type Breaker struct {
+	name     string
+	cfg      Config
+	interval time.Duration
+
+	mu            sync.Mutex
+	state         State
+	openedAt      time.Time
+	halfOpenCalls int
+	buckets       []bucket
+}
+
+// New creates a Breaker with the specified name and configuration.
+func New(name string, cfg Config) *Breaker {
+	if cfg.Window <= 0 {
+		cfg.Window = 10 * time.Second
+	}
+	if cfg.Buckets <= 0 {
+		cfg.Buckets = 10
+	}
+	if cfg.FailureRatio <= 0 {
+		cfg.FailureRatio = 0.5
+	}
+	if cfg.MinRequests <= 0 {
+		cfg.MinRequests = 10
+	}
+	if cfg.OpenTimeout <= 0 {
+		cfg.OpenTimeout = 30 * time.Second
+	}
+	if cfg.HalfOpenMaxCalls <= 0 {
+		cfg.HalfOpenMaxCalls = 1
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+
+	interval := cfg.Window / time.Duration(cfg.Buckets)
+	if interval <= 0 {
+		interval = 1
+	}
+
+	return &Breaker{
+		name:     name,
+		cfg:      cfg,
+		interval: interval,
+		buckets:  make([]bucket, cfg.Buckets),
+	}
+}
+
+// Name returns the name of this Breaker.
+func (b *Breaker) Name() string {
+	return b.name
+}
+
+// State returns the current state of this Breaker.
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.currentState(b.cfg.Now())
+}
+
+// Counts returns the numbers of the outcomes in the current window.
+func (b *Breaker) Counts() Counts {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.counts(b.epoch(b.cfg.Now()))
+}
+
+// Allow returns errs.Ok if a call is allowed, or otherwise returns an errs.Err whose reason is
+// CircuitOpen. The location of the errs.Err is the caller of this method.
+// If this returns errs.Ok, the outcome of the call should be recorded with Record.
+func (b *Breaker) Allow() errs.Err {
+	if b.allow() {
+		return errs.Ok()
+	}
+	return errs.NewSkip(1, CircuitOpen{Name: b.name})
+}
+
+// Do calls the specified function if a call is allowed, records the returned errs.Err as the
+// outcome, and returns it.
+// If a call is not allowed, this method returns an errs.Err whose reason is CircuitOpen without
+// calling the function. The location of the errs.Err is the caller of this method.
+func (b *Breaker) Do(fn func() errs.Err) errs.Err {
+	if !b.allow() {
+		return errs.NewSkip(1, CircuitOpen{Name: b.name})
+	}
+	e := fn()
+	b.Record(e)
+	return e
+}
+
+// Record records the specified errs.Err as the outcome of a call.
+func (b *Breaker) Record(e errs.Err) {
+	b.record(e, b.cfg.Now())
+}
+
+// HandleErr records the specified errs.Err as the outcome at the specified time.
+// This method has the signature of an error handler, so this can be added with
+// errs.AddSyncErrHandler or errs.AddAsyncErrHandler. An errs.Err whose reason is CircuitOpen is
+// ignored, so that the errs.Err values returned by the breakers are not counted.
+func (b *Breaker) HandleErr(e errs.Err, tm time.Time) {
+	if errs.HasReason[CircuitOpen](e) {
+		return
+	}
+	b.record(e, tm)
+}
+
+func (b *Breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.currentState(b.cfg.Now()) {
+	case Closed:
+		return true
+	case HalfOpen:
+		if b.halfOpenCalls < b.cfg.HalfOpenMaxCalls {
+			b.halfOpenCalls++
+			return true
+		}
+	}
+	return false
+}
+
+// currentState returns the state at the specified time, moving to HalfOpen if OpenTimeout has
+// passed since the breaker opened.
+func (b *Breaker) currentState(now time.Time) State {
+	if b.state == Open && now.Sub(b.openedAt) >= b.cfg.OpenTimeout {
+		b.state = HalfOpen
+		b.halfOpenCalls = 0
+	}
+	return b.state
+}
+
+func (b *Breaker) record(e errs.Err, tm time.Time) {
+	failure := e.IsNotOk() && (b.cfg.IsFailure == nil || b.cfg.IsFailure(e))
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	epoch := b.epoch(tm)
+	bk := b.bucket(epoch)
+	if bk == nil {
+		return
+	}
+
+	if !failure {
+		bk.successes++
+		if b.currentState(b.cfg.Now()) == HalfOpen {
+			b.close()
+		}
+		return
+	}
+
+	if bk.failures == nil {
+		bk.failures = make(map[reflect.Type]int)
+	}
+	bk.failures[errs.ReasonTypeOf(e.Reason())]++
+
+	switch b.currentState(b.cfg.Now()) {
+	case Closed:
+		c := b.counts(b.epoch(b.cfg.Now()))
+		total := c.Total()
+		if total >= b.cfg.MinRequests &&
+			float64(c.TotalFailures())/float64(total) > b.cfg.FailureRatio {
+			b.open()
+		}
+	case HalfOpen:
+		b.open()
+	}
+}
+
+func (b *Breaker) open() {
+	b.state = Open
+	b.openedAt = b.cfg.Now()
+}
+
+func (b *Breaker) close() {
+	b.state = Closed
+	for i := range b.buckets {
+		b.buckets[i] = bucket{}
+	}
+}
+
+func (b *Breaker) epoch(tm time.Time) int64 {
+	return tm.UnixNano() / int64(b.interval)
+}
+
+// bucket returns the bucket for the specified epoch, resetting it if it holds an expired epoch.
+// If the epoch has already expired from the window, or the bucket holds a newer epoch, this
+// returns nil.
+func (b *Breaker) bucket(epoch int64) *bucket {
+	n := int64(len(b.buckets))
+	if epoch <= b.epoch(b.cfg.Now())-n {
+		return nil
+	}
+	bk := &b.buckets[((epoch%n)+n)%n]
+	if bk.epoch > epoch {
+		return nil
+	}
+	if bk.epoch != epoch {
+		*bk = bucket{epoch: epoch}
+	}
+	return bk
+}
+
+func (b *Breaker) counts(epoch int64) Counts {
+	c := Counts{Failures: map[reflect.Type]int{}}
+	n := int64(len(b.buckets))
+	for _, bk := range b.buckets {
+		if bk.epoch <= epoch-n || bk.epoch > epoch {
+			continue
+		}
+		c.Successes += bk.successes
+		for t, f := range bk.failures {
+			c.Failures[t] += f
+		}
+	}
+	return c
+}
@@ -0,0 +1,264 @@
+package breaker_test
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/errs"
+	"github.com/sttk/errs/breaker"
+)
+
+type /* error reasons */ (
+	Timeout struct{}
+
+	Unavailable struct{}
+
+	InvalidValue struct {
+		Name string
+	}
+)
+
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newTestBreaker(clock *fakeClock) *breaker.Breaker {
+	return breaker.New("test", breaker.Config{
+		Window:       10 * time.Second,
+		Buckets:      10,
+		FailureRatio: 0.5,
+		MinRequests:  4,
+		OpenTimeout:  5 * time.Second,
+		Now:          clock.Now,
+	})
+}
+
+func TestState_String(t *testing.T) {
+	assert.Equal(t, breaker.Closed.String(), "closed")
+	assert.Equal(t, breaker.Open.String(), "open")
+	assert.Equal(t, breaker.HalfOpen.String(), "half-open")
+	assert.Equal(t, breaker.State(99).String(), "unknown")
+}
+
+func TestBreaker_counts(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	b := newTestBreaker(clock)
+	assert.Equal(t, b.Name(), "test")
+
+	b.Record(errs.Ok())
+	b.Record(errs.New(Timeout{}))
+	b.Record(errs.New(&Timeout{}))
+	b.Record(errs.New(Unavailable{}))
+
+	c := b.Counts()
+	assert.Equal(t, c.Successes, 1)
+	assert.Equal(t, c.Failures, map[reflect.Type]int{
+		reflect.TypeOf(Timeout{}):     2,
+		reflect.TypeOf(Unavailable{}): 1,
+	})
+	assert.Equal(t, c.Total(), 4)
+	assert.Equal(t, c.TotalFailures(), 3)
+}
+
+func TestBreaker_slidingWindow(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	b := newTestBreaker(clock)
+
+	b.Record(errs.Ok())
+	clock.Advance(5 * time.Second)
+	b.Record(errs.New(Timeout{}))
+	assert.Equal(t, b.Counts().Total(), 2)
+
+	clock.Advance(5 * time.Second)
+	c := b.Counts()
+	assert.Equal(t, c.Successes, 0)
+	assert.Equal(t, c.TotalFailures(), 1)
+
+	clock.Advance(5 * time.Second)
+	assert.Equal(t, b.Counts().Total(), 0)
+
+	b.HandleErr(errs.New(Timeout{}), clock.Now().Add(-20*time.Second))
+	assert.Equal(t, b.Counts().Total(), 0)
+}
+
+func TestBreaker_open(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	b := newTestBreaker(clock)
+
+	b.Record(errs.Ok())
+	b.Record(errs.New(Timeout{}))
+	b.Record(errs.New(Timeout{}))
+	assert.Equal(t, b.State(), breaker.Closed)
+
+	b.Record(errs.Ok())
+	assert.Equal(t, b.State(), breaker.Closed)
+
+	b.Record(errs.New(Unavailable{}))
+	assert.Equal(t, b.State(), breaker.Open)
+
+	e := b.Allow()
+	assert.Equal(t, e.Reason(), breaker.CircuitOpen{Name: "test"})
+	assert.Equal(t, e.File(), "breaker_test.go")
+	assert.Equal(t, e.Line(), 117)
+
+	var called bool
+	e = b.Do(func() errs.Err {
+		called = true
+		return errs.Ok()
+	})
+	assert.False(t, called)
+	assert.Equal(t, e.Reason(), breaker.CircuitOpen{Name: "test"})
+	assert.Equal(t, e.Line(), 123)
+}
+
+func TestBreaker_halfOpen(t *testing.T) {
+	setup := func() (*fakeClock, *breaker.Breaker) {
+		clock := &fakeClock{now: time.Unix(1000, 0)}
+		b := newTestBreaker(clock)
+		for i := 0; i < 4; i++ {
+			b.Record(errs.New(Timeout{}))
+		}
+		assert.Equal(t, b.State(), breaker.Open)
+		clock.Advance(5 * time.Second)
+		assert.Equal(t, b.State(), breaker.HalfOpen)
+		return clock, b
+	}
+
+	t.Run("close after a successful trial", func(t *testing.T) {
+		_, b := setup()
+
+		assert.True(t, b.Allow().IsOk())
+		assert.Equal(t, b.Allow().Reason(), breaker.CircuitOpen{Name: "test"})
+
+		b.Record(errs.Ok())
+		assert.Equal(t, b.State(), breaker.Closed)
+		assert.Equal(t, b.Counts().Total(), 0)
+		assert.True(t, b.Allow().IsOk())
+	})
+
+	t.Run("open again after a failed trial", func(t *testing.T) {
+		clock, b := setup()
+
+		e := b.Do(func() errs.Err {
+			return errs.New(Timeout{})
+		})
+		assert.Equal(t, e.Reason(), Timeout{})
+		assert.Equal(t, b.State(), breaker.Open)
+
+		clock.Advance(4 * time.Second)
+		assert.Equal(t, b.State(), breaker.Open)
+		clock.Advance(time.Second)
+		assert.Equal(t, b.State(), breaker.HalfOpen)
+	})
+}
+
+func TestBreaker_isFailure(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	b := breaker.New("test", breaker.Config{
+		MinRequests: 2,
+		IsFailure: func(e errs.Err) bool {
+			return !errs.HasReason[InvalidValue](e)
+		},
+		Now: clock.Now,
+	})
+
+	b.Record(errs.New(InvalidValue{Name: "abc"}))
+	b.Record(errs.New(InvalidValue{Name: "def"}))
+	assert.Equal(t, b.State(), breaker.Closed)
+	assert.Equal(t, b.Counts().Successes, 2)
+
+	b.Record(errs.New(Timeout{}))
+	b.Record(errs.New(Timeout{}))
+	b.Record(errs.New(Timeout{}))
+	assert.Equal(t, b.State(), breaker.Open)
+}
+
+func TestBreaker_HandleErr(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	b := newTestBreaker(clock)
+
+	for i := 0; i < 3; i++ {
+		b.HandleErr(errs.New(Timeout{}), clock.Now())
+	}
+	assert.Equal(t, b.State(), breaker.Closed)
+
+	b.HandleErr(errs.New(breaker.CircuitOpen{Name: "other"}), clock.Now())
+	b.HandleErr(errs.New("abc", errs.New(breaker.CircuitOpen{Name: "other"})), clock.Now())
+	assert.Equal(t, b.State(), breaker.Closed)
+
+	b.HandleErr(errs.New(Timeout{}), clock.Now())
+	assert.Equal(t, b.State(), breaker.Open)
+}
+
+func TestBreaker_concurrentUse(t *testing.T) {
+	b := breaker.New("test", breaker.Config{MinRequests: 1000000})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				b.Do(func() errs.Err {
+					if j%2 == 0 {
+						return errs.New(Timeout{}, errors.New("abc"))
+					}
+					return errs.Ok()
+				})
+			}
+		}()
+	}
+	wg.Wait()
+
+	c := b.Counts()
+	assert.Equal(t, c.Successes, 400)
+	assert.Equal(t, c.TotalFailures(), 400)
+}
+
+// The error handlers cannot be cleared from outside of the errs package, so the handler is
+// registered only once and forwards to the breaker of the current run. This allows the test to run
+// repeatedly in the same process, for example with -count=2.
+var (
+	handlerOnce    sync.Once
+	handlerBreaker atomic.Value
+)
+
+func TestBreaker_fedByErrHandlers(t *testing.T) {
+	b := breaker.New("test", breaker.Config{MinRequests: 3})
+	handlerBreaker.Store(b)
+
+	handlerOnce.Do(func() {
+		errs.EnableErrNotification()
+		errs.AddSyncReasonHandler(func(r Timeout, e errs.Err, tm time.Time) {
+			handlerBreaker.Load().(*breaker.Breaker).HandleErr(e, tm)
+		})
+		errs.FixErrHandlers()
+	})
+
+	errs.New(Timeout{})
+	errs.New(Timeout{})
+	errs.New(InvalidValue{Name: "abc"})
+	assert.Equal(t, b.State(), breaker.Closed)
+
+	errs.New(Timeout{})
+	assert.Equal(t, b.State(), breaker.Open)
+	assert.Equal(t, b.Counts().TotalFailures(), 3)
+}
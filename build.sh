@@ -18,6 +18,8 @@ format() {
 }
 
 compile() {
+  go vet ./...
+  errcheck $?
   go vet -tags github.sttk.errs.notify ./...
   errcheck $?
   go build -tags github.sttk.errs.notify
@@ -25,6 +27,8 @@ compile() {
 }
 
 test() {
+  go test -v $(go list ./... | grep -v /benchmark)
+  errcheck $?
   go test -tags github.sttk.errs.notify -v $(go list ./... | grep -v /benchmark)
   errcheck $?
 }
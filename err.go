@@ -12,9 +12,12 @@
 // representing the reason is useful for identifying the specific error, locating where it
 // occurred, or generating appropriate error messages, etc.
 //
-// Optionally, by using the build tag: github.sttk.errs.notify and registering error handlers
-// in advance, it is possible to receive notifications either synchronously or asynchronously
-// at the time the error struct is created.
+// An Err records the file name and line number where it was created. Optionally, by setting a
+// depth with SetStackTraceDepth, it can also capture the full call stack at that time.
+//
+// Optionally, by enabling the notification and registering error handlers in advance, it is
+// possible to receive notifications either synchronously or asynchronously at the time the error
+// struct is created.
 //
 // # Install
 //
@@ -45,6 +48,131 @@
 //	    ...
 //	}
 //
+// Match dispatches an Err to a case by the reason type, normalizing pointer and value reasons,
+// and returns a value.
+//
+//	msg := errs.Match(err,
+//	    errs.Case(func(r IllegalState) string { return r.State }),
+//	    errs.Default(func(e errs.Err) string { return e.Error() }),
+//	)
+//
+// A reason in the chain of an arbitrary error, including an Err wrapped in another error and
+// multi-errors, can be extracted with ReasonOf and checked with HasReason.
+//
+//	if r, ok := errs.ReasonOf[IllegalState](err); ok {
+//	    fmt.Printf("state = %s\n", r.State)
+//	}
+//
+// errors.Is also works with reasons. A target Err created with the zero value of a reason type
+// matches any Err with a reason of that type, and a target with another reason matches an Err
+// with an equal reason.
+//
+//	if errors.Is(err, errs.Target(IllegalState{})) {
+//	    ...
+//	}
+//
+// Use Target rather than New to create the target, because errors.Is compares the target with an
+// Err with == and the comparison of Err(s) panics if the reasons hold incomparable values.
+//
+// # Multiple causes
+//
+// An Err retains all of the causes supplied to New. Cause returns the first one, Causes returns all
+// of them, and errors.Is and errors.As examine every cause.
+//
+//	err := errs.New(FailToSyncAll{}, err1, err2)
+//	errors.Is(err, err2) // true
+//
+// On Go 1.20 or later, the Unwrap method returns []error, so errors.Unwrap returns nil for an Err
+// even if it has a cause. This is a breaking change from the earlier versions, which returned the
+// cause with errors.Unwrap; use Cause instead.
+//
+// # Error routing
+//
+// IfOkThen, IfNotOkThen, OrElse, MapReason, Finally and Recover route an error to the
+// following operations declaratively.
+//
+//	err = errs.Recover(err.MapReason(toConfigReason), func(r InvalidConfig) errs.Err {
+//	    return useDefaultConfig()
+//	})
+//
+// # Results
+//
+// Result[T] pairs a value with an Err. ResultOf creates a Result from a pair of (T, error), and
+// Map, AndThen and the methods of Result chain operations which may fail. Get converts it back to
+// a pair of (T, error) whose error is nil when no error occurred.
+//
+//	r := errs.AndThen(errs.ResultOf(os.ReadFile(path)), parseConfig)
+//	cfg, err := r.Get()
+//
+// # Panic recovery
+//
+// RecoverPanic, Safe and Go convert a panic into an Err whose reason is Panicked. The location
+// and the stack trace of the Err are those of the panic site, and the Err is notified to the
+// error handlers.
+//
+//	func doSomething() (err errs.Err) {
+//	    defer errs.RecoverPanic(&err)
+//	    ...
+//	}
+//
+// # Concurrent tasks
+//
+// Group runs tasks returning Err(s) concurrently with an optional limit, and Wait returns an Err
+// whose reason is Aggregated and whose causes are Err(s) with TaskFailed reasons, one for each
+// failed task. In the CancelOnFirstErr mode, the context is canceled when a task fails first.
+//
+//	g, ctx := errs.NewGroup(ctx, errs.CollectAll)
+//	for _, url := range urls {
+//	    url := url
+//	    g.GoLabeled(url, func() errs.Err { return fetch(ctx, url) })
+//	}
+//	err := g.Wait()
+//
+// # Partial failures
+//
+// BatchErr records the Err(s) of the failed items in a bulk operation keyed by their indexes or
+// IDs, and reports the numbers of the failed items per reason type and the success ratio.
+// Like Err, a BatchErr can be formatted, serialized to JSON, and logged with log/slog.
+//
+//	b := errs.NewBatchErr(len(rows))
+//	for i, row := range rows {
+//	    b.Add(i, insert(row))
+//	}
+//	return b.AsError()
+//
+// # JSON serialization
+//
+// An Err can be marshalled to and unmarshalled from JSON with encoding/json.
+// To reconstruct the original typed reason when unmarshalling, register the reason type with
+// RegisterReason in advance. Otherwise, the reason becomes an UnknownReason.
+//
+//	func init() {
+//	    errs.RegisterReason[IllegalState]()
+//	}
+//
+// # Logging with log/slog
+//
+// On Go 1.21 or later, Err implements slog.LogValuer, so an Err is logged as a group of its
+// reason type, reason fields, file, line and cause chain.
+// Moreover, by decorating a slog.Handler with NewSlogHandler, Err(s) wrapped in other errors are
+// also expanded in the same form.
+//
+//	logger := slog.New(errs.NewSlogHandler(slog.NewJSONHandler(os.Stdout, nil)))
+//	logger.Error("failed", "err", err)
+//
+// # Stack traces
+//
+// By default, an Err records only the file name and line number where it was created.
+// To capture the full call stack, set the maximum depth of stack frames with
+// SetStackTraceDepth. The captured frames can be obtained with StackTrace method.
+//
+//	errs.SetStackTraceDepth(32)
+//
+//	err := errs.New(IllegalState{State: "bad state"})
+//	for _, f := range err.StackTrace() {
+//	    fmt.Printf("%s\n\t%s:%d\n", f.Function, f.File, f.Line)
+//	}
+//
 // # Notification of Err instantiations
 //
 // This package optionally provides a feature to notify pre-registered error handlers when an Err
@@ -58,6 +186,8 @@
 // Error notifications will not occur until the FixErrHandlers function is called.
 // This function locks the current set of error handlers, preventing further additions and enabling
 // notification processing.
+// Handlers can be registered and fixed safely from any goroutine, for example in init functions of
+// several packages, even while other goroutines are already creating Err(s).
 //
 //	errs.AddAsyncErrHandler(func(err errs.Err, tm time.Time) {
 //	    fmt.Printf("%s (%s:%d) %v\n",
@@ -71,10 +201,31 @@
 //
 //	errs.FixErrHandlers()
 //
-// NOTE: To use this feature, it is necessary to specify the following build tag to go build
-// command:
+// To register error handlers which receive notifications only for Err(s) with a specific reason
+// type, use the AddSyncReasonHandler and AddAsyncReasonHandler functions.
+//
+//	errs.AddSyncReasonHandler(func(r IllegalState, err errs.Err, tm time.Time) {
+//	    // ...
+//	});
+//
+// Asynchronous handlers run on a bounded pool of worker goroutines, which can be configured with
+// the SetAsyncErrHandlerPool function.
+//
+// When the process exits, call the ShutdownErrHandlers function to stop notifications and wait
+// for the pending invocations of asynchronous handlers to finish.
+//
+// A panic in an error handler is recovered and reported to stderr, or passed to the hook set with
+// the SetErrHandlerPanicHook function.
+//
+// The handler API is always available, so libraries can register handlers without requiring
+// anything from the programs using them. The notification itself is disabled by default, and it
+// can be enabled at runtime with the EnableErrNotification function or with the environment
+// variable GITHUB_STTK_ERRS_NOTIFY=1. It is enabled by default when the program is built with the
+// following build tag:
 //
 //	go build -tags github.sttk.errs.notify
+//
+// While the notification is disabled, creating an Err costs only a single atomic load for it.
 package errs
 
 import (
@@ -96,17 +247,25 @@ import (
 // The reason for the error can be distinguished with a type switch statement, and type casting,
 // so it is easy to handle the error in a type-safe manner.
 //
-// This struct also contains an optional cause error, which is the error caused the current error.
-// This is useful for chaining errors.
+// This struct also contains optional cause errors, which are the errors caused the current error.
+// This is useful for chaining errors, and for reporting every underlying failure of a fan-out
+// operation.
 //
 // This struct is implements the Error method, so it can be used as an error object in Go programs.
 // And since this struct implements the Unwrap method, it can be used as a wrapper error object in
 // Go programs.
+// This struct also implements the Is and As methods, so errors.Is can check the reason of an Err
+// in an error chain against a target Err, and errors.As can extract a reason which implements
+// error.
+// This struct also implements the Format method of fmt.Formatter, so the verbs %v, %+v and %#v
+// print a short message, a detailed multi-line view, and a Go-syntax representation respectively.
 type Err struct {
 	reason any
 	file   string
 	line   int
 	cause  error
+	causes *[]error
+	stack  *callStack
 }
 
 // Ok returns an instance of Err with no reason, indicating no error.
@@ -116,26 +275,63 @@ func Ok() Err {
 }
 
 // New creates a new Err instance with the provided reason.
-// Optionally, a cause can also be supplied, which represents a lower-level error.
+// Optionally, causes can also be supplied, which represent lower-level errors.
+// All of the causes are retained, except nil values.
 func New(reason any, cause ...error) Err {
+	return newErr(1, reason, cause)
+}
+
+// NewSkip creates a new Err instance with the provided reason like New, but records the location
+// of a caller further up the call stack.
+// The argument skip is the number of stack frames to skip, with 0 identifying the caller of
+// NewSkip, so NewSkip(0, reason) is equivalent to New(reason).
+//
+// This function is useful for helper functions which create an Err on behalf of their callers.
+func NewSkip(skip int, reason any, cause ...error) Err {
+	return newErr(skip+1, reason, cause)
+}
+
+func newErr(skip int, reason any, cause []error) Err {
 	var e Err
 	e.reason = reason
 
-	if len(cause) > 0 {
-		e.cause = cause[0]
-	}
+	e.setCauses(cause)
 
-	_, file, line, ok := runtime.Caller(1)
+	_, file, line, ok := runtime.Caller(skip + 1)
 	if ok {
 		e.file = filepath.Base(file)
 		e.line = line
 	}
 
+	e.stack = captureStack(skip + 1)
+
 	notifyErr(e)
 
 	return e
 }
 
+func (e *Err) setCauses(causes []error) {
+	if len(causes) == 1 {
+		e.cause = causes[0]
+		return
+	}
+
+	var cs []error
+	for _, c := range causes {
+		if c != nil {
+			cs = append(cs, c)
+		}
+	}
+	switch len(cs) {
+	case 0:
+	case 1:
+		e.cause = cs[0]
+	default:
+		e.cause = cs[0]
+		e.causes = &cs
+	}
+}
+
 // Reason returns the reason for the error, which can be any type.
 // This helps in analyzing why the error occurred.
 func (e Err) Reason() any {
@@ -152,26 +348,55 @@ func (e Err) Line() int {
 	return e.line
 }
 
+// StackTrace returns the frames of the call stack captured when this Err was created.
+// The first frame is the caller of New.
+// If stack trace capturing was disabled at that time, this method returns nil.
+// Stack trace capturing is enabled with SetStackTraceDepth.
+func (e Err) StackTrace() []Frame {
+	return e.stack.frames()
+}
+
 // Error returns a string representation of the Err instance.
-// It formats the error, including the package path, reason, and cause.
+// It formats the error, including the package path, reason, and causes.
 func (e Err) Error() string {
 	if e.reason == nil { // Ok
 		return "github.com/sttk/errs.Err {}"
 	}
 
-	v := reflect.ValueOf(e.reason)
+	reason := reasonString(e.reason)
+
+	if e.cause == nil {
+		return fmt.Sprintf("github.com/sttk/errs.Err {reason:%s file:%s line:%d}",
+			reason, e.file, e.line)
+	}
+	if e.causes == nil {
+		return fmt.Sprintf("github.com/sttk/errs.Err {reason:%s file:%s line:%d cause:%s}",
+			reason, e.file, e.line, e.cause)
+	}
+	return fmt.Sprintf("github.com/sttk/errs.Err {reason:%s file:%s line:%d causes:[%s]}",
+		reason, e.file, e.line, joinErrors(*e.causes, ", ", "%s"))
+}
+
+func joinErrors(errs []error, sep, format string) string {
+	var b strings.Builder
+	for i, err := range errs {
+		if i > 0 {
+			b.WriteString(sep)
+		}
+		fmt.Fprintf(&b, format, err)
+	}
+	return b.String()
+}
+
+func reasonString(r any) string {
+	v := reflect.ValueOf(r)
 	if v.Kind() == reflect.Ptr {
 		v = v.Elem()
 	}
 	var reason string
 	if v.Kind() == reflect.Struct {
-		t := v.Type()
-		reason = t.PkgPath()
-		if len(reason) > 0 {
-			reason += "."
-		}
-		reason += t.Name()
-		flds := fmt.Sprintf("%+v", e.reason)
+		reason = structTypeName(v.Type())
+		flds := fmt.Sprintf("%+v", r)
 		if strings.HasPrefix(flds, "&") {
 			flds = flds[1:]
 		}
@@ -181,27 +406,66 @@ func (e Err) Error() string {
 	} else if v.CanInterface() {
 		reason = fmt.Sprintf("%v", v.Interface())
 	}
+	return reason
+}
 
-	if e.cause == nil {
-		return fmt.Sprintf("github.com/sttk/errs.Err {reason:%s file:%s line:%d}",
-			reason, e.file, e.line)
+// castReason converts the reason to the type T.
+// In addition to a type assertion, this function converts a pointer reason to its pointed value
+// if T is the pointed type, and a value reason to a pointer to its copy if T is the pointer type.
+func castReason[T any](reason any) (T, bool) {
+	if r, ok := reason.(T); ok {
+		return r, true
+	}
+
+	var zero T
+	v := reflect.ValueOf(reason)
+	if !v.IsValid() {
+		return zero, false
 	}
-	return fmt.Sprintf("github.com/sttk/errs.Err {reason:%s file:%s line:%d cause:%s}",
-		reason, e.file, e.line, e.cause)
+
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if v.Kind() == reflect.Ptr && v.Type().Elem() == t {
+		if v.IsNil() {
+			return zero, false
+		}
+		return v.Elem().Interface().(T), true
+	}
+	if t.Kind() == reflect.Ptr && t.Elem() == v.Type() {
+		p := reflect.New(v.Type())
+		p.Elem().Set(v)
+		return p.Interface().(T), true
+	}
+	return zero, false
 }
 
-// Unwrap returns the underlying cause of the error, allowing it to be chained.
-// This helps in accessing the root cause when errors are wrapped.
-func (e Err) Unwrap() error {
-	return e.cause
+func structTypeName(t reflect.Type) string {
+	name := t.PkgPath()
+	if len(name) > 0 {
+		name += "."
+	}
+	return name + t.Name()
 }
 
 // Cause returns the cause of the error.
-// This is similar to Unwrap but provides a direct access method.
+// If the error has multiple causes, this method returns the first one.
 func (e Err) Cause() error {
 	return e.cause
 }
 
+// Causes returns all of the causes of the error.
+// If the error has no cause, this method returns nil.
+func (e Err) Causes() []error {
+	if e.causes != nil {
+		cs := make([]error, len(*e.causes))
+		copy(cs, *e.causes)
+		return cs
+	}
+	if e.cause != nil {
+		return []error{e.cause}
+	}
+	return nil
+}
+
 // IsOk returns true if the Err instance has no reason, indicating no error.
 // This is used to check if the operation was successful.
 func (e Err) IsOk() bool {
@@ -214,6 +478,17 @@ func (e Err) IsNotOk() bool {
 	return (e.reason != nil)
 }
 
+// AsError returns nil if the Err instance has no reason, and otherwise returns the Err instance as
+// an error.
+// Since Err is a struct, an Err indicating no error converted to error is not nil. Use this method
+// to return an Err from a function whose result type is error.
+func (e Err) AsError() error {
+	if e.IsOk() {
+		return nil
+	}
+	return e
+}
+
 // IfOkThen executes the provided function if no error is present (IsOk).
 // This is useful for chaining operations that only proceed if no error has occurred.
 func (e Err) IfOkThen(fn func() Err) Err {
@@ -222,3 +497,65 @@ func (e Err) IfOkThen(fn func() Err) Err {
 	}
 	return e
 }
+
+// IfNotOkThen executes the provided function with this Err if an error is present (IsNotOk), and
+// returns this Err as it is.
+// This is useful for side effects on an error, such as logging, in a chain of operations.
+func (e Err) IfNotOkThen(fn func(Err)) Err {
+	if e.IsNotOk() {
+		fn(e)
+	}
+	return e
+}
+
+// OrElse returns the Err which the provided function returns if an error is present (IsNotOk),
+// or otherwise returns this Err.
+// This is useful for replacing an error with another error, or for recovering from an error by
+// returning Ok.
+func (e Err) OrElse(fn func(Err) Err) Err {
+	if e.IsNotOk() {
+		return fn(e)
+	}
+	return e
+}
+
+// MapReason returns a copy of this Err whose reason is transformed by the provided function if an
+// error is present (IsNotOk), or otherwise returns this Err.
+// The copy retains the causes, the location and the stack trace of this Err, and it is not
+// notified to the error handlers since it is not a new instantiation.
+// If the function returns nil, this method returns Ok.
+func (e Err) MapReason(fn func(any) any) Err {
+	if e.IsOk() {
+		return e
+	}
+	reason := fn(e.reason)
+	if reason == nil {
+		return Ok()
+	}
+	e.reason = reason
+	return e
+}
+
+// Finally executes the provided function regardless of whether an error is present, and returns
+// this Err as it is.
+func (e Err) Finally(fn func()) Err {
+	fn()
+	return e
+}
+
+// Recover returns the Err which the provided function returns if the reason of the specified Err
+// is of the type parameter T, or otherwise returns the specified Err.
+// The function can handle the reason and return Ok, or return a new Err.
+// A reason of the pointer type to T, or a reason of the pointed type if T is a pointer type, also
+// matches, and it is passed to the function after being converted to T.
+//
+// Since methods cannot have type parameters in Go, this is provided as a function.
+func Recover[T any](e Err, fn func(T) Err) Err {
+	if e.IsOk() {
+		return e
+	}
+	if r, ok := castReason[T](e.reason); ok {
+		return fn(r)
+	}
+	return e
+}
@@ -176,7 +176,7 @@ func TestErr(t *testing.T) {
 	t.Run("apply errors.Is", func(t *testing.T) {
 		t.Run("ok", func(t *testing.T) {
 			err := errs.Ok()
-			assert.Nil(t, err.Unwrap())
+			assert.Nil(t, err.Cause())
 
 			err0 := errs.Ok()
 			err1 := errs.New("def")
@@ -194,7 +194,7 @@ func TestErr(t *testing.T) {
 
 		t.Run("reason is a value and with no cause", func(t *testing.T) {
 			err := errs.New(InvalidValue{Name: "foo", Value: "abc"})
-			assert.Nil(t, err.Unwrap())
+			assert.Nil(t, err.Cause())
 
 			err0 := errs.Ok()
 			err1 := errs.New("def")
@@ -206,13 +206,13 @@ func TestErr(t *testing.T) {
 			assert.False(t, errors.Is(err, err0))
 			assert.False(t, errors.Is(err, err1))
 			assert.False(t, errors.Is(err, err2))
-			assert.False(t, errors.Is(err, err3))
-			assert.False(t, errors.Is(err, err4))
+			assert.True(t, errors.Is(err, err3))
+			assert.True(t, errors.Is(err, err4))
 		})
 
 		t.Run("reason is a pointer and with no cause", func(t *testing.T) {
 			err := errs.New(&InvalidValue{Name: "foo", Value: "abc"})
-			assert.Nil(t, err.Unwrap())
+			assert.Nil(t, err.Cause())
 
 			err0 := errs.Ok()
 			err1 := errs.New("def")
@@ -224,14 +224,14 @@ func TestErr(t *testing.T) {
 			assert.False(t, errors.Is(err, err0))
 			assert.False(t, errors.Is(err, err1))
 			assert.False(t, errors.Is(err, err2))
-			assert.False(t, errors.Is(err, err3))
-			assert.False(t, errors.Is(err, err4))
+			assert.True(t, errors.Is(err, err3))
+			assert.True(t, errors.Is(err, err4))
 		})
 
 		t.Run("reason is a value and with cause", func(t *testing.T) {
 			cause := errors.New("def")
 			err := errs.New(InvalidValue{Name: "foo", Value: "abc"}, cause)
-			assert.Equal(t, err.Unwrap(), cause)
+			assert.Equal(t, err.Cause(), cause)
 
 			err0 := errs.Ok()
 			err1 := errs.New("def")
@@ -247,12 +247,12 @@ func TestErr(t *testing.T) {
 			assert.False(t, errors.Is(err, err0))
 			assert.False(t, errors.Is(err, err1))
 			assert.False(t, errors.Is(err, err2))
-			assert.False(t, errors.Is(err, err3))
-			assert.False(t, errors.Is(err, err4))
-			assert.False(t, errors.Is(err, err5))
-			assert.False(t, errors.Is(err, err6))
-			assert.False(t, errors.Is(err, err7))
-			assert.False(t, errors.Is(err, err8))
+			assert.True(t, errors.Is(err, err3))
+			assert.True(t, errors.Is(err, err4))
+			assert.True(t, errors.Is(err, err5))
+			assert.True(t, errors.Is(err, err6))
+			assert.True(t, errors.Is(err, err7))
+			assert.True(t, errors.Is(err, err8))
 
 			assert.False(t, errors.Is(err, err1))
 			assert.False(t, errors.Is(err0, err1))
@@ -280,7 +280,7 @@ func TestErr(t *testing.T) {
 		t.Run("reason is a pointer and with cause", func(t *testing.T) {
 			cause := errors.New("def")
 			err := errs.New(&InvalidValue{Name: "foo", Value: "abc"}, cause)
-			assert.Equal(t, err.Unwrap(), cause)
+			assert.Equal(t, err.Cause(), cause)
 
 			err0 := errs.Ok()
 			err1 := errs.New("def")
@@ -296,12 +296,12 @@ func TestErr(t *testing.T) {
 			assert.False(t, errors.Is(err, err0))
 			assert.False(t, errors.Is(err, err1))
 			assert.False(t, errors.Is(err, err2))
-			assert.False(t, errors.Is(err, err3))
-			assert.False(t, errors.Is(err, err4))
-			assert.False(t, errors.Is(err, err5))
-			assert.False(t, errors.Is(err, err6))
-			assert.False(t, errors.Is(err, err7))
-			assert.False(t, errors.Is(err, err8))
+			assert.True(t, errors.Is(err, err3))
+			assert.True(t, errors.Is(err, err4))
+			assert.True(t, errors.Is(err, err5))
+			assert.True(t, errors.Is(err, err6))
+			assert.True(t, errors.Is(err, err7))
+			assert.True(t, errors.Is(err, err8))
 
 			assert.False(t, errors.Is(err, err1))
 			assert.False(t, errors.Is(err0, err1))
@@ -330,7 +330,7 @@ func TestErr(t *testing.T) {
 	t.Run("apply errors.As", func(t *testing.T) {
 		t.Run("ok", func(t *testing.T) {
 			err := errs.Ok()
-			assert.Nil(t, err.Unwrap())
+			assert.Nil(t, err.Cause())
 
 			var err0 errs.Err
 			//var err1 error
@@ -345,7 +345,7 @@ func TestErr(t *testing.T) {
 
 		t.Run("reason is a value and with no cause", func(t *testing.T) {
 			err := errs.New(FailToGetValue{Name: "foo"})
-			assert.Nil(t, err.Unwrap())
+			assert.Nil(t, err.Cause())
 
 			var err0 errs.Err
 			// var err1 error
@@ -360,7 +360,7 @@ func TestErr(t *testing.T) {
 
 		t.Run("reason is a pointer and with no cause", func(t *testing.T) {
 			err := errs.New(&FailToGetValue{Name: "foo"})
-			assert.Nil(t, err.Unwrap())
+			assert.Nil(t, err.Cause())
 
 			var err0 errs.Err
 			// var err1 error
@@ -376,7 +376,7 @@ func TestErr(t *testing.T) {
 		t.Run("reason is a value and with cause", func(t *testing.T) {
 			cause := InvalidValueError{Name: "a", Value: "b"}
 			err := errs.New(InvalidValue{Name: "foo", Value: "abc"}, cause)
-			assert.Equal(t, err.Unwrap(), cause)
+			assert.Equal(t, err.Cause(), cause)
 
 			var err0 errs.Err
 			// var err1 error
@@ -395,7 +395,7 @@ func TestErr(t *testing.T) {
 		t.Run("reason is a pointer and with cause", func(t *testing.T) {
 			cause := InvalidValueError{Name: "a", Value: "b"}
 			err := errs.New(&FailToGetValue{Name: "foo"}, cause)
-			assert.Equal(t, err.Unwrap(), cause)
+			assert.Equal(t, err.Cause(), cause)
 
 			var err0 errs.Err
 			// var err1 error
@@ -491,12 +491,270 @@ func TestErr(t *testing.T) {
 	t.Run("Print", func(t *testing.T) {
 		t.Run("%v", func(t *testing.T) {
 			err := errs.New(InvalidValue{Name: "abc", Value: "def"})
-			assert.Equal(t, fmt.Sprintf("%v", err), `github.com/sttk/errs.Err {reason:github.com/sttk/errs_test.InvalidValue{Name:abc Value:def} file:err_test.go line:493}`)
+			assert.Equal(t, fmt.Sprintf("%v", err), `github.com/sttk/errs_test.InvalidValue{Name:abc Value:def}`)
 		})
 
 		t.Run("%w", func(t *testing.T) {
 			err := errs.New(InvalidValue{Name: "abc", Value: "def"})
-			assert.Equal(t, fmt.Errorf("%w", err).Error(), `github.com/sttk/errs.Err {reason:github.com/sttk/errs_test.InvalidValue{Name:abc Value:def} file:err_test.go line:498}`)
+			assert.Equal(t, fmt.Errorf("%w", err).Error(), `github.com/sttk/errs_test.InvalidValue{Name:abc Value:def}`)
+		})
+	})
+}
+
+func newErrForCaller(reason any) errs.Err {
+	return errs.NewSkip(1, reason)
+}
+
+func TestNewSkip(t *testing.T) {
+	t.Run("skip 0", func(t *testing.T) {
+		err := errs.NewSkip(0, "abc")
+		assert.Equal(t, err.Reason(), "abc")
+		assert.Equal(t, err.File(), "err_test.go")
+		assert.Equal(t, err.Line(), 510)
+	})
+
+	t.Run("skip 1", func(t *testing.T) {
+		err := newErrForCaller("abc")
+		assert.Equal(t, err.Reason(), "abc")
+		assert.Equal(t, err.File(), "err_test.go")
+		assert.Equal(t, err.Line(), 517)
+	})
+
+	t.Run("with cause", func(t *testing.T) {
+		cause := errors.New("def")
+		err := errs.NewSkip(0, "abc", cause)
+		assert.Equal(t, err.Cause(), cause)
+	})
+}
+
+func TestNew_multipleCauses(t *testing.T) {
+	t.Run("no cause", func(t *testing.T) {
+		err := errs.New("abc")
+		assert.Nil(t, err.Cause())
+		assert.Nil(t, err.Causes())
+	})
+
+	t.Run("a cause", func(t *testing.T) {
+		cause := errors.New("def")
+		err := errs.New("abc", cause)
+		assert.Equal(t, err.Cause(), cause)
+		assert.Equal(t, err.Causes(), []error{cause})
+		assert.Equal(t, err.Error(), "github.com/sttk/errs.Err {reason:abc file:err_test.go line:539 cause:def}")
+	})
+
+	t.Run("multiple causes", func(t *testing.T) {
+		cause1 := errors.New("def")
+		cause2 := errs.New(FailToGetValue{Name: "foo"})
+		err := errs.New("abc", cause1, nil, cause2)
+		assert.Equal(t, err.Cause(), cause1)
+		assert.Equal(t, err.Causes(), []error{cause1, cause2})
+		assert.Equal(t, err.Error(), "github.com/sttk/errs.Err {reason:abc file:err_test.go line:548 causes:[def, github.com/sttk/errs.Err {reason:github.com/sttk/errs_test.FailToGetValue{Name:foo} file:err_test.go line:547}]}")
+
+		causes := err.Causes()
+		causes[0] = nil
+		assert.Equal(t, err.Causes(), []error{cause1, cause2})
+	})
+
+	t.Run("nil causes", func(t *testing.T) {
+		err := errs.New("abc", nil, nil)
+		assert.Nil(t, err.Cause())
+		assert.Nil(t, err.Causes())
+
+		cause := errors.New("def")
+		err = errs.New("abc", nil, cause)
+		assert.Equal(t, err.Cause(), cause)
+		assert.Equal(t, err.Causes(), []error{cause})
+	})
+
+	t.Run("errors.Is and errors.As examine all causes", func(t *testing.T) {
+		cause1 := errors.New("def")
+		cause2 := InvalidValueError{Name: "bar", Value: "ghi"}
+		err := fmt.Errorf("wrapped: %w", errs.New("abc", cause1, cause2))
+
+		assert.True(t, errors.Is(err, cause1))
+		assert.True(t, errors.Is(err, cause2))
+		assert.False(t, errors.Is(err, errors.New("def")))
+
+		var e InvalidValueError
+		assert.True(t, errors.As(err, &e))
+		assert.Equal(t, e, cause2)
+	})
+
+	t.Run("reasons in all causes", func(t *testing.T) {
+		err := errs.New("abc", errors.New("def"), errs.New(FailToGetValue{Name: "foo"}))
+		r, ok := errs.ReasonOf[FailToGetValue](err)
+		assert.True(t, ok)
+		assert.Equal(t, r, FailToGetValue{Name: "foo"})
+	})
+}
+
+func TestErr_AsError(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		assert.Nil(t, errs.Ok().AsError())
+	})
+
+	t.Run("not ok", func(t *testing.T) {
+		err := errs.New(InvalidValue{Name: "foo"})
+		assert.Equal(t, err.AsError(), error(err))
+	})
+}
+
+func TestErr_IfNotOkThen(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		var done bool
+		err := errs.Ok().IfNotOkThen(func(e errs.Err) {
+			done = true
+		})
+		assert.True(t, err.IsOk())
+		assert.False(t, done)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		err0 := errs.New(InvalidValue{Name: "abc", Value: "def"})
+
+		var got errs.Err
+		err := err0.IfNotOkThen(func(e errs.Err) {
+			got = e
+		})
+		assert.Equal(t, err, err0)
+		assert.Equal(t, got, err0)
+	})
+}
+
+func TestErr_OrElse(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		var done bool
+		err := errs.Ok().OrElse(func(e errs.Err) errs.Err {
+			done = true
+			return errs.New(FailToGetValue{Name: "abc"})
+		})
+		assert.True(t, err.IsOk())
+		assert.False(t, done)
+	})
+
+	t.Run("error -> ok", func(t *testing.T) {
+		err := errs.New(InvalidValue{Name: "abc"}).OrElse(func(e errs.Err) errs.Err {
+			return errs.Ok()
+		})
+		assert.True(t, err.IsOk())
+	})
+
+	t.Run("error -> error", func(t *testing.T) {
+		err0 := errs.New(InvalidValue{Name: "abc"})
+		err := err0.OrElse(func(e errs.Err) errs.Err {
+			return errs.New(FailToGetValue{Name: "def"}, e)
+		})
+		assert.Equal(t, err.Reason(), FailToGetValue{Name: "def"})
+		assert.Equal(t, err.Cause(), err0)
+	})
+}
+
+func TestErr_MapReason(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		var done bool
+		err := errs.Ok().MapReason(func(r any) any {
+			done = true
+			return FailToGetValue{Name: "abc"}
+		})
+		assert.True(t, err.IsOk())
+		assert.False(t, done)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		cause1 := errors.New("def")
+		cause2 := errors.New("ghi")
+		err0 := errs.New(InvalidValue{Name: "abc"}, cause1, cause2)
+
+		err := err0.MapReason(func(r any) any {
+			return FailToGetValue{Name: r.(InvalidValue).Name}
+		})
+		assert.Equal(t, err.Reason(), FailToGetValue{Name: "abc"})
+		assert.Equal(t, err.Causes(), []error{cause1, cause2})
+		assert.Equal(t, err.File(), err0.File())
+		assert.Equal(t, err.Line(), err0.Line())
+		assert.Equal(t, err.StackTrace(), err0.StackTrace())
+		assert.Equal(t, err0.Reason(), InvalidValue{Name: "abc"})
+	})
+
+	t.Run("error -> nil", func(t *testing.T) {
+		err := errs.New(InvalidValue{Name: "abc"}).MapReason(func(r any) any {
+			return nil
+		})
+		assert.True(t, err.IsOk())
+		assert.Equal(t, err, errs.Ok())
+	})
+}
+
+func TestErr_Finally(t *testing.T) {
+	var count int
+
+	err := errs.Ok().Finally(func() {
+		count++
+	})
+	assert.True(t, err.IsOk())
+	assert.Equal(t, count, 1)
+
+	err0 := errs.New(InvalidValue{Name: "abc"})
+	err = err0.Finally(func() {
+		count++
+	})
+	assert.Equal(t, err, err0)
+	assert.Equal(t, count, 2)
+}
+
+func TestRecover(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		var done bool
+		err := errs.Recover(errs.Ok(), func(r InvalidValue) errs.Err {
+			done = true
+			return errs.New(FailToGetValue{Name: "abc"})
+		})
+		assert.True(t, err.IsOk())
+		assert.False(t, done)
+	})
+
+	t.Run("matched -> ok", func(t *testing.T) {
+		var got InvalidValue
+		err := errs.Recover(errs.New(InvalidValue{Name: "abc"}), func(r InvalidValue) errs.Err {
+			got = r
+			return errs.Ok()
+		})
+		assert.True(t, err.IsOk())
+		assert.Equal(t, got, InvalidValue{Name: "abc"})
+	})
+
+	t.Run("matched -> error", func(t *testing.T) {
+		err0 := errs.New(InvalidValue{Name: "abc"})
+		err := errs.Recover(err0, func(r InvalidValue) errs.Err {
+			return errs.New(FailToGetValue{Name: r.Name}, err0)
+		})
+		assert.Equal(t, err.Reason(), FailToGetValue{Name: "abc"})
+		assert.Equal(t, err.Cause(), err0)
+	})
+
+	t.Run("matched with a pointer", func(t *testing.T) {
+		err := errs.Recover(errs.New(&InvalidValue{Name: "abc"}), func(r InvalidValue) errs.Err {
+			assert.Equal(t, r.Name, "abc")
+			return errs.Ok()
+		})
+		assert.True(t, err.IsOk())
+
+		err = errs.Recover(errs.New(InvalidValue{Name: "abc"}), func(r *InvalidValue) errs.Err {
+			assert.Equal(t, r.Name, "abc")
+			return errs.Ok()
+		})
+		assert.True(t, err.IsOk())
+	})
+
+	t.Run("not matched", func(t *testing.T) {
+		err0 := errs.New(FailToGetValue{Name: "abc"})
+
+		var done bool
+		err := errs.Recover(err0, func(r InvalidValue) errs.Err {
+			done = true
+			return errs.Ok()
 		})
+		assert.Equal(t, err, err0)
+		assert.False(t, done)
 	})
 }
@@ -0,0 +1,123 @@
+// Copyright (C) 2026 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package errshttp
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/sttk/errs"
+)
+
+// RemoteReason is the reason of an errs.Err decoded from a problem details response when the
+// reason type in the response is not registered with errs.RegisterReason, or when the reason
+// cannot be decoded into the registered type.
+// Type is the fully qualified type name of the remote reason, and Fields is the reason's fields
+// decoded as a JSON object. If the remote reason is not a JSON object, Fields is nil.
+type RemoteReason struct {
+	Type   string
+	Fields map[string]any
+}
+
+// UnexpectedStatus is the reason of an errs.Err decoded from an error response which is not a
+// problem details document, or which does not have a reason type.
+type UnexpectedStatus struct {
+	StatusCode int
+}
+
+// ResponseError is the cause of an errs.Err decoded from an error response, and records the
+// response.
+// Header is a copy of the header of the response. Problem is the problem details document in the
+// response, which is zero if the response is not a problem details document. RemoteFile and
+// RemoteLine are the file and the line where the errs.Err was created on the remote side, which
+// are empty and zero if the response does not include them. (See EnableProblemLocation.)
+type ResponseError struct {
+	StatusCode int
+	Status     string
+	Header     http.Header
+	Problem    Problem
+	RemoteFile string
+	RemoteLine int
+}
+
+// Error returns the status of the response and the remote location if it is available.
+func (e *ResponseError) Error() string {
+	if len(e.RemoteFile) > 0 {
+		return fmt.Sprintf("errshttp: response %s (remote %s:%d)", e.Status, e.RemoteFile, e.RemoteLine)
+	}
+	return fmt.Sprintf("errshttp: response %s", e.Status)
+}
+
+const maxProblemSize = 1 << 20
+
+// FromResponse creates an errs.Err from the specified HTTP response.
+// If the status code of the response is less than 400, this function returns errs.Ok() without
+// reading the response body.
+//
+// If the response is a problem details document with a reason type, the reason of the returned
+// errs.Err is reconstructed as a value of the type registered with errs.RegisterReason, or is a
+// RemoteReason if the type is not registered. Otherwise, the reason is UnexpectedStatus.
+// The cause of the returned errs.Err is a *ResponseError which records the response and the
+// remote location if it is available.
+// The returned errs.Err records the location of the caller of this function.
+//
+// This function reads the response body, but does not close it.
+func FromResponse(resp *http.Response) errs.Err {
+	if resp.StatusCode < 400 {
+		return errs.Ok()
+	}
+
+	cause := &ResponseError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Header:     resp.Header.Clone(),
+	}
+
+	if !isProblemResponse(resp) {
+		return errs.NewSkip(1, UnexpectedStatus{StatusCode: resp.StatusCode}, cause)
+	}
+
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxProblemSize))
+	if err != nil || json.Unmarshal(b, &cause.Problem) != nil {
+		cause.Problem = Problem{}
+		return errs.NewSkip(1, UnexpectedStatus{StatusCode: resp.StatusCode}, cause)
+	}
+	cause.RemoteFile = cause.Problem.File
+	cause.RemoteLine = cause.Problem.Line
+
+	if len(cause.Problem.ReasonType) == 0 {
+		return errs.NewSkip(1, UnexpectedStatus{StatusCode: resp.StatusCode}, cause)
+	}
+
+	return errs.NewSkip(1, decodeReason(cause.Problem), cause)
+}
+
+func isProblemResponse(resp *http.Response) bool {
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	return err == nil && mediaType == ProblemContentType
+}
+
+// decodeReason reconstructs the reason in the problem details document as the registered type,
+// or returns a RemoteReason if the type is not registered or the reason cannot be decoded into
+// the registered type.
+func decodeReason(p Problem) any {
+	if b, err := json.Marshal(reasonJSON{ReasonType: p.ReasonType, Reason: p.Reason}); err == nil {
+		var e errs.Err
+		if err := json.Unmarshal(b, &e); err == nil {
+			if _, unknown := e.Reason().(errs.UnknownReason); !unknown {
+				return e.Reason()
+			}
+		}
+	}
+
+	remote := RemoteReason{Type: p.ReasonType}
+	if err := json.Unmarshal(p.Reason, &remote.Fields); err != nil {
+		remote.Fields = nil
+	}
+	return remote
+}
@@ -0,0 +1,183 @@
+package errshttp_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/errs"
+	"github.com/sttk/errs/errshttp"
+)
+
+func init() {
+	errs.RegisterReason[NotFound]()
+}
+
+func newTestServer(e errs.Err) *httptest.Server {
+	return httptest.NewServer(errshttp.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) errs.Err {
+			return e
+		},
+	))
+}
+
+func TestFromResponse(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		srv := newTestServer(errs.Ok())
+		defer srv.Close()
+
+		resp, err := http.Get(srv.URL)
+		assert.Nil(t, err)
+		defer resp.Body.Close()
+
+		e := errshttp.FromResponse(resp)
+		assert.True(t, e.IsOk())
+	})
+
+	t.Run("reason type is registered", func(t *testing.T) {
+		errshttp.EnableProblemLocation()
+		defer errshttp.DisableProblemLocation()
+
+		srv := newTestServer(errs.New(NotFound{ID: "a"}))
+		defer srv.Close()
+
+		resp, err := http.Get(srv.URL)
+		assert.Nil(t, err)
+		defer resp.Body.Close()
+
+		e := errshttp.FromResponse(resp)
+		assert.Equal(t, e.Reason(), NotFound{ID: "a"})
+		assert.Equal(t, e.File(), "client_test.go")
+		assert.Equal(t, e.Line(), 51)
+
+		var re *errshttp.ResponseError
+		assert.True(t, errors.As(e, &re))
+		assert.Equal(t, re.StatusCode, 404)
+		assert.Equal(t, re.Status, "404 Not Found")
+		assert.Equal(t, re.RemoteFile, "client_test.go")
+		assert.Equal(t, re.RemoteLine, 44)
+		assert.Equal(t, re.Problem.Title, "Not Found")
+		assert.Equal(t, re.Error(), "errshttp: response 404 Not Found (remote client_test.go:44)")
+	})
+
+	t.Run("reason type is not registered", func(t *testing.T) {
+		srv := newTestServer(errs.New(InvalidValue{Name: "foo", Value: "abc"}))
+		defer srv.Close()
+
+		resp, err := http.Get(srv.URL)
+		assert.Nil(t, err)
+		defer resp.Body.Close()
+
+		e := errshttp.FromResponse(resp)
+		assert.Equal(t, e.Reason(), errshttp.RemoteReason{
+			Type:   "github.com/sttk/errs/errshttp_test.InvalidValue",
+			Fields: map[string]any{"Name": "foo", "Value": "abc"},
+		})
+
+		var re *errshttp.ResponseError
+		assert.True(t, errors.As(e, &re))
+		assert.Equal(t, re.StatusCode, 400)
+		assert.Equal(t, re.RemoteFile, "")
+		assert.Equal(t, re.RemoteLine, 0)
+		assert.Equal(t, re.Error(), "errshttp: response 400 Bad Request")
+	})
+
+	t.Run("reason does not match the registered type", func(t *testing.T) {
+		resp := &http.Response{
+			StatusCode: 404,
+			Status:     "404 Not Found",
+			Header:     http.Header{"Content-Type": {"application/problem+json"}},
+			Body:       newBody(`{"type":"about:blank","status":404,"reason_type":"github.com/sttk/errs/errshttp_test.NotFound","reason":{"ID":1}}`),
+		}
+		e := errshttp.FromResponse(resp)
+		assert.Equal(t, e.Reason(), errshttp.RemoteReason{
+			Type:   "github.com/sttk/errs/errshttp_test.NotFound",
+			Fields: map[string]any{"ID": 1.0},
+		})
+	})
+
+	t.Run("response header", func(t *testing.T) {
+		resp := &http.Response{
+			StatusCode: 503,
+			Status:     "503 Service Unavailable",
+			Header:     http.Header{"Content-Type": {"text/plain"}, "Retry-After": {"120"}},
+			Body:       newBody("unavailable"),
+		}
+		e := errshttp.FromResponse(resp)
+
+		var re *errshttp.ResponseError
+		assert.True(t, errors.As(e, &re))
+		assert.Equal(t, re.Header.Get("Retry-After"), "120")
+
+		resp.Header.Set("Retry-After", "0")
+		assert.Equal(t, re.Header.Get("Retry-After"), "120")
+	})
+
+	t.Run("reason is not an object", func(t *testing.T) {
+		resp := &http.Response{
+			StatusCode: 500,
+			Status:     "500 Internal Server Error",
+			Header:     http.Header{"Content-Type": {"application/problem+json; charset=utf-8"}},
+			Body:       newBody(`{"type":"about:blank","status":500,"reason_type":"foo.Bar","reason":[1,2]}`),
+		}
+		e := errshttp.FromResponse(resp)
+		assert.Equal(t, e.Reason(), errshttp.RemoteReason{Type: "foo.Bar"})
+	})
+
+	t.Run("not a problem details document", func(t *testing.T) {
+		resp := &http.Response{
+			StatusCode: 502,
+			Status:     "502 Bad Gateway",
+			Header:     http.Header{"Content-Type": {"text/plain"}},
+			Body:       newBody("bad gateway"),
+		}
+		e := errshttp.FromResponse(resp)
+		assert.Equal(t, e.Reason(), errshttp.UnexpectedStatus{StatusCode: 502})
+
+		var re *errshttp.ResponseError
+		assert.True(t, errors.As(e, &re))
+		assert.Equal(t, re.Problem, errshttp.Problem{})
+		assert.Equal(t, re.Error(), "errshttp: response 502 Bad Gateway")
+	})
+
+	t.Run("problem details document without reason", func(t *testing.T) {
+		resp := &http.Response{
+			StatusCode: 503,
+			Status:     "503 Service Unavailable",
+			Header:     http.Header{"Content-Type": {"application/problem+json"}},
+			Body:       newBody(`{"type":"about:blank","title":"Service Unavailable","status":503}`),
+		}
+		e := errshttp.FromResponse(resp)
+		assert.Equal(t, e.Reason(), errshttp.UnexpectedStatus{StatusCode: 503})
+
+		var re *errshttp.ResponseError
+		assert.True(t, errors.As(e, &re))
+		assert.Equal(t, re.Problem.Title, "Service Unavailable")
+	})
+
+	t.Run("broken problem details document", func(t *testing.T) {
+		resp := &http.Response{
+			StatusCode: 500,
+			Status:     "500 Internal Server Error",
+			Header:     http.Header{"Content-Type": {"application/problem+json"}},
+			Body:       newBody(`{"type":`),
+		}
+		e := errshttp.FromResponse(resp)
+		assert.Equal(t, e.Reason(), errshttp.UnexpectedStatus{StatusCode: 500})
+	})
+}
+
+type body struct {
+	*strings.Reader
+}
+
+func (b body) Close() error {
+	return nil
+}
+
+func newBody(s string) body {
+	return body{strings.NewReader(s)}
+}
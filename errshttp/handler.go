@@ -0,0 +1,70 @@
+// Copyright (C) 2026 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package errshttp
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/sttk/errs"
+)
+
+// HandlerFunc is the type of HTTP handler functions which return an errs.Err.
+// If the returned errs.Err indicates an error, it is written to the response as a problem
+// details document with WriteProblem.
+//
+// The function must not write to the response before returning an error, because the status
+// code and the body of the problem details document cannot be written after that.
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) errs.Err
+
+// ServeHTTP implements http.Handler, and calls the function f.
+func (f HandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if e := f(w, r); e.IsNotOk() {
+		WriteProblem(w, e)
+	}
+}
+
+// Middleware returns an http.Handler which calls the next handler and converts an errs.Err
+// panicked by it into a problem details document with WriteProblem.
+// An error value which wraps an errs.Err is also converted.
+//
+// Other panics, including http.ErrAbortHandler, are re-panicked as they are.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			e, ok := panickedErr(v)
+			if !ok {
+				panic(v)
+			}
+			WriteProblem(w, e)
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func panickedErr(v any) (errs.Err, bool) {
+	switch p := v.(type) {
+	case errs.Err:
+		return p, p.IsNotOk()
+	case *errs.Err:
+		if p != nil {
+			return *p, p.IsNotOk()
+		}
+	case error:
+		if p == http.ErrAbortHandler {
+			return errs.Ok(), false
+		}
+		var e errs.Err
+		if errors.As(p, &e) {
+			return e, e.IsNotOk()
+		}
+	}
+	return errs.Ok(), false
+}
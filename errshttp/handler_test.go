@@ -0,0 +1,97 @@
+package errshttp_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/errs"
+	"github.com/sttk/errs/errshttp"
+)
+
+func TestHandlerFunc(t *testing.T) {
+	t.Run("return an error", func(t *testing.T) {
+		h := errshttp.HandlerFunc(func(w http.ResponseWriter, r *http.Request) errs.Err {
+			return errs.New(NotFound{ID: "a"})
+		})
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", "/items?id=a", nil))
+
+		assert.Equal(t, w.Code, http.StatusNotFound)
+		assert.Equal(t, w.Header().Get("Content-Type"), "application/problem+json")
+		assert.Equal(t, w.Body.String(), `{"type":"about:blank","title":"Not Found","status":404,"reason_type":"github.com/sttk/errs/errshttp_test.NotFound","reason":{"ID":"a"}}`)
+	})
+
+	t.Run("return ok", func(t *testing.T) {
+		h := errshttp.HandlerFunc(func(w http.ResponseWriter, r *http.Request) errs.Err {
+			w.WriteHeader(http.StatusNoContent)
+			return errs.Ok()
+		})
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", "/items?id=a", nil))
+
+		assert.Equal(t, w.Code, http.StatusNoContent)
+		assert.Equal(t, w.Body.String(), "")
+	})
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Run("panic with an Err", func(t *testing.T) {
+		h := errshttp.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic(errs.New(&Conflict{}))
+		}))
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+		assert.Equal(t, w.Code, http.StatusConflict)
+		assert.Equal(t, w.Body.String(), `{"type":"about:blank","title":"Conflict","status":409,"reason_type":"*github.com/sttk/errs/errshttp_test.Conflict","reason":{}}`)
+	})
+
+	t.Run("panic with an error wrapping an Err", func(t *testing.T) {
+		h := errshttp.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic(fmt.Errorf("wrapped: %w", errs.New(NotFound{ID: "b"})))
+		}))
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+		assert.Equal(t, w.Code, http.StatusNotFound)
+		assert.Equal(t, w.Body.String(), `{"type":"about:blank","title":"Not Found","status":404,"reason_type":"github.com/sttk/errs/errshttp_test.NotFound","reason":{"ID":"b"}}`)
+	})
+
+	t.Run("re-panic with other values", func(t *testing.T) {
+		h := errshttp.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic("abc")
+		}))
+
+		assert.PanicsWithValue(t, "abc", func() {
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+		})
+	})
+
+	t.Run("re-panic with http.ErrAbortHandler", func(t *testing.T) {
+		h := errshttp.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic(http.ErrAbortHandler)
+		}))
+
+		assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+		})
+	})
+
+	t.Run("not panic", func(t *testing.T) {
+		h := errshttp.Middleware(errshttp.HandlerFunc(func(w http.ResponseWriter, r *http.Request) errs.Err {
+			return errs.New(InvalidValue{Name: "foo"})
+		}))
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+		assert.Equal(t, w.Code, http.StatusBadRequest)
+	})
+}
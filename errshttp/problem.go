@@ -0,0 +1,113 @@
+// Copyright (C) 2026 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package errshttp
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/sttk/errs"
+)
+
+// ProblemContentType is the media type of a problem details document.
+const ProblemContentType = "application/problem+json"
+
+// Problem is the struct which represents an RFC 9457 problem details document rendered from an
+// errs.Err.
+// Type, Title, Status, Detail and Instance are the standard members, and ReasonType, Reason,
+// File and Line are the extension members which carry the reason and the location of the
+// errs.Err.
+type Problem struct {
+	Type       string          `json:"type,omitempty"`
+	Title      string          `json:"title,omitempty"`
+	Status     int             `json:"status,omitempty"`
+	Detail     string          `json:"detail,omitempty"`
+	Instance   string          `json:"instance,omitempty"`
+	ReasonType string          `json:"reason_type,omitempty"`
+	Reason     json.RawMessage `json:"reason,omitempty"`
+	File       string          `json:"file,omitempty"`
+	Line       int             `json:"line,omitempty"`
+}
+
+type reasonJSON struct {
+	ReasonType string          `json:"reason_type"`
+	Reason     json.RawMessage `json:"reason"`
+}
+
+var problemLocationEnabled int32
+
+// EnableProblemLocation makes the problem details documents include the file and the line where
+// the errs.Err was created.
+// The location is not included by default, because it exposes the internal structure of the
+// server to clients.
+func EnableProblemLocation() {
+	atomic.StoreInt32(&problemLocationEnabled, 1)
+}
+
+// DisableProblemLocation makes the problem details documents exclude the file and the line where
+// the errs.Err was created. This is the default.
+func DisableProblemLocation() {
+	atomic.StoreInt32(&problemLocationEnabled, 0)
+}
+
+// IsProblemLocationEnabled reports whether the problem details documents include the file and the
+// line where the errs.Err was created.
+func IsProblemLocationEnabled() bool {
+	return atomic.LoadInt32(&problemLocationEnabled) != 0
+}
+
+// NewProblem creates a Problem from the specified errs.Err.
+// The type is "about:blank", and the title is the status text of the status code determined by
+// StatusOf. The cause of the errs.Err is never included.
+//
+// The reason type and the reason are included only if the reason implements StatusReason or its
+// type is registered with RegisterStatus, and the status code is less than 500. So the reason of
+// an unexpected error is not exposed to clients. The reason is output in the same form as
+// errs.Err.MarshalJSON, and if the reason cannot be marshalled to JSON, the reason type and the
+// reason are omitted.
+//
+// The file and the line are included only if EnableProblemLocation has been called.
+func NewProblem(e errs.Err) Problem {
+	status := StatusOf(e)
+	p := Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+	}
+	if e.IsOk() {
+		return p
+	}
+
+	if _, mapped := mappedStatus(e.Reason()); mapped && status < 500 {
+		var doc reasonJSON
+		if b, err := e.MarshalJSON(); err == nil && json.Unmarshal(b, &doc) == nil {
+			p.ReasonType = doc.ReasonType
+			p.Reason = doc.Reason
+		}
+	}
+	if IsProblemLocationEnabled() {
+		p.File = e.File()
+		p.Line = e.Line()
+	}
+	return p
+}
+
+// WriteProblem writes the problem details document rendered from the specified errs.Err to the
+// response, with the status code determined by StatusOf and the content type
+// application/problem+json.
+func WriteProblem(w http.ResponseWriter, e errs.Err) {
+	p := NewProblem(e)
+	b, err := json.Marshal(p)
+	if err != nil {
+		http.Error(w, p.Title, p.Status)
+		return
+	}
+
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+	_, _ = w.Write(b)
+}
@@ -0,0 +1,110 @@
+package errshttp_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/errs"
+	"github.com/sttk/errs/errshttp"
+)
+
+type /* error reasons */ (
+	ConflictCode int
+
+	Unmarshallable struct {
+		Fn func()
+	}
+)
+
+func (r ConflictCode) HTTPStatus() int {
+	return http.StatusConflict
+}
+
+func (r Unmarshallable) HTTPStatus() int {
+	return http.StatusBadRequest
+}
+
+func TestNewProblem(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		p := errshttp.NewProblem(errs.Ok())
+		assert.Equal(t, p, errshttp.Problem{Type: "about:blank", Title: "OK", Status: 200})
+	})
+
+	t.Run("reason is a struct", func(t *testing.T) {
+		e := errs.New(InvalidValue{Name: "foo", Value: "abc", Password: "xxx"}, errors.New("secret"))
+		b, err := json.Marshal(errshttp.NewProblem(e))
+		assert.Nil(t, err)
+		assert.Equal(t, string(b), `{"type":"about:blank","title":"Bad Request","status":400,"reason_type":"github.com/sttk/errs/errshttp_test.InvalidValue","reason":{"Name":"foo","Value":"abc"}}`)
+	})
+
+	t.Run("reason is not a struct", func(t *testing.T) {
+		e := errs.New(ConflictCode(3))
+		b, err := json.Marshal(errshttp.NewProblem(e))
+		assert.Nil(t, err)
+		assert.Equal(t, string(b), `{"type":"about:blank","title":"Conflict","status":409,"reason_type":"github.com/sttk/errs/errshttp_test.ConflictCode","reason":3}`)
+	})
+
+	t.Run("reason cannot be marshalled", func(t *testing.T) {
+		e := errs.New(Unmarshallable{Fn: func() {}})
+		b, err := json.Marshal(errshttp.NewProblem(e))
+		assert.Nil(t, err)
+		assert.Equal(t, string(b), `{"type":"about:blank","title":"Bad Request","status":400}`)
+	})
+
+	t.Run("reason is not mapped", func(t *testing.T) {
+		e := errs.New(struct{ Password string }{"hunter2"})
+		b, err := json.Marshal(errshttp.NewProblem(e))
+		assert.Nil(t, err)
+		assert.Equal(t, string(b), `{"type":"about:blank","title":"Internal Server Error","status":500}`)
+
+		b, err = json.Marshal(errshttp.NewProblem(errs.New("abc")))
+		assert.Nil(t, err)
+		assert.Equal(t, string(b), `{"type":"about:blank","title":"Internal Server Error","status":500}`)
+	})
+
+	t.Run("status code is 5xx", func(t *testing.T) {
+		e := errs.New(InvalidStatus{Status: http.StatusServiceUnavailable})
+		b, err := json.Marshal(errshttp.NewProblem(e))
+		assert.Nil(t, err)
+		assert.Equal(t, string(b), `{"type":"about:blank","title":"Service Unavailable","status":503}`)
+	})
+
+	t.Run("location is enabled", func(t *testing.T) {
+		errshttp.EnableProblemLocation()
+		defer errshttp.DisableProblemLocation()
+		assert.True(t, errshttp.IsProblemLocationEnabled())
+
+		e := errs.New(NotFound{ID: "a"})
+		b, err := json.Marshal(errshttp.NewProblem(e))
+		assert.Nil(t, err)
+		assert.Equal(t, string(b), `{"type":"about:blank","title":"Not Found","status":404,"reason_type":"github.com/sttk/errs/errshttp_test.NotFound","reason":{"ID":"a"},"file":"problem_test.go","line":81}`)
+
+		e = errs.New("abc")
+		b, err = json.Marshal(errshttp.NewProblem(e))
+		assert.Nil(t, err)
+		assert.Equal(t, string(b), `{"type":"about:blank","title":"Internal Server Error","status":500,"file":"problem_test.go","line":86}`)
+	})
+
+	t.Run("location is disabled by default", func(t *testing.T) {
+		assert.False(t, errshttp.IsProblemLocationEnabled())
+	})
+}
+
+func TestWriteProblem(t *testing.T) {
+	w := httptest.NewRecorder()
+	errshttp.WriteProblem(w, errs.New(NotFound{ID: "a"}))
+
+	assert.Equal(t, w.Code, http.StatusNotFound)
+	assert.Equal(t, w.Header().Get("Content-Type"), "application/problem+json")
+	assert.Equal(t, w.Body.String(), `{"type":"about:blank","title":"Not Found","status":404,"reason_type":"github.com/sttk/errs/errshttp_test.NotFound","reason":{"ID":"a"}}`)
+}
+
+func TestWriteProblem_invalidStatus(t *testing.T) {
+	w := httptest.NewRecorder()
+	errshttp.WriteProblem(w, errs.New(InvalidStatus{Status: 0}))
+	assert.Equal(t, w.Code, http.StatusInternalServerError)
+}
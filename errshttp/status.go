@@ -0,0 +1,152 @@
+// Copyright (C) 2026 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+// Package errshttp is for exposing errs.Err values through HTTP APIs.
+//
+// This package maps the reason of an errs.Err to an HTTP status code, renders an errs.Err as an
+// RFC 9457 problem details document (application/problem+json), and provides an http.Handler
+// adapter and a middleware which convert an errs.Err returned or panicked by a handler into such
+// a response.
+//
+// # Status codes
+//
+// The status code for an errs.Err is determined by its reason. If the reason has the method
+// HTTPStatus() int, the result of the method is used.
+// Otherwise, the status code registered for the reason type with RegisterStatus is used.
+// If neither is available, or the status code is not between 400 and 599, the status code is
+// 500 Internal Server Error.
+//
+//	type NotFound struct {
+//	    ID string
+//	}
+//
+//	func (r NotFound) HTTPStatus() int { return http.StatusNotFound }
+//
+//	func init() {
+//	    errshttp.RegisterStatus[InvalidValue](http.StatusBadRequest)
+//	}
+//
+// # Problem details
+//
+// A problem details document rendered from an errs.Err contains the standard members type,
+// title and status, and the extension members reason_type and reason. The reason type and the
+// reason are included only for a reason whose status code is mapped with StatusReason or
+// RegisterStatus and is less than 500. The extension members file and line are included only
+// after EnableProblemLocation is called:
+//
+//	{
+//	  "type": "about:blank",
+//	  "title": "Not Found",
+//	  "status": 404,
+//	  "reason_type": "github.com/foo/bar.NotFound",
+//	  "reason": {"ID": "abc"},
+//	  "file": "bar.go",
+//	  "line": 123
+//	}
+//
+// The reason is marshalled with encoding/json, so only the exported fields of a struct reason are
+// output, and a field can be hidden from clients with the struct tag `json:"-"`.
+// The cause of an errs.Err is never output, because it may contain internal information.
+//
+// # Handlers
+//
+//	http.Handle("/items", errshttp.Middleware(errshttp.HandlerFunc(
+//	    func(w http.ResponseWriter, r *http.Request) errs.Err {
+//	        item, e := findItem(r.URL.Query().Get("id"))
+//	        if e.IsNotOk() {
+//	            return e
+//	        }
+//	        ...
+//	        return errs.Ok()
+//	    },
+//	)))
+//
+// # Clients
+//
+// FromResponse rebuilds an errs.Err from an error response. The reason is reconstructed as the
+// type registered with errs.RegisterReason, or is a RemoteReason holding the type name and the
+// fields if the type is not registered. The cause is a *ResponseError which records the response,
+// and the file and the line where the errs.Err was created on the remote side if the response
+// includes them.
+//
+//	resp, err := http.Get(url)
+//	if err != nil {
+//	    return errs.New(FailToCallService{}, err)
+//	}
+//	defer resp.Body.Close()
+//	if e := errshttp.FromResponse(resp); e.IsNotOk() {
+//	    return e
+//	}
+package errshttp
+
+import (
+	"net/http"
+	"reflect"
+	"sync"
+
+	"github.com/sttk/errs"
+)
+
+// StatusReason is the interface which a reason can implement to specify the HTTP status code
+// for the errs.Err which has the reason.
+type StatusReason interface {
+	HTTPStatus() int
+}
+
+var (
+	statusRegistry = map[reflect.Type]int{}
+	statusMutex    sync.RWMutex
+)
+
+// RegisterStatus registers the HTTP status code for the reason type T.
+// The status code is used for an errs.Err whose reason is either a T or a *T, so RegisterStatus[T]
+// and RegisterStatus[*T] are equivalent.
+// If the reason implements StatusReason, the result of its HTTPStatus method takes precedence
+// over the registered status code.
+//
+// The status codes should be registered before serving requests, for example in an init function
+// of the package which defines the reason types.
+func RegisterStatus[T any](status int) {
+	t := errs.ReasonType[T]()
+
+	statusMutex.Lock()
+	defer statusMutex.Unlock()
+	statusRegistry[t] = status
+}
+
+// StatusOf returns the HTTP status code for the specified errs.Err.
+// If the errs.Err indicates no error, this function returns 200 OK.
+// If the status code of the reason is not an error status code, which is between 400 and 599,
+// this function returns 500 Internal Server Error instead.
+func StatusOf(e errs.Err) int {
+	reason := e.Reason()
+	if reason == nil {
+		return http.StatusOK
+	}
+
+	status, ok := mappedStatus(reason)
+	if !ok || status < 400 || status > 599 {
+		return http.StatusInternalServerError
+	}
+	return status
+}
+
+// mappedStatus returns the status code which the reason specifies with StatusReason or which is
+// registered for the reason type, and reports whether the status code is mapped.
+func mappedStatus(reason any) (int, bool) {
+	if r, ok := reason.(StatusReason); ok {
+		return r.HTTPStatus(), true
+	}
+	v := reflect.ValueOf(reason)
+	if v.Kind() == reflect.Ptr && !v.IsNil() {
+		if r, ok := v.Elem().Interface().(StatusReason); ok {
+			return r.HTTPStatus(), true
+		}
+	}
+
+	statusMutex.RLock()
+	defer statusMutex.RUnlock()
+	status, ok := statusRegistry[errs.ReasonTypeOf(reason)]
+	return status, ok
+}
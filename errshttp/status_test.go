@@ -0,0 +1,80 @@
+package errshttp_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/errs"
+	"github.com/sttk/errs/errshttp"
+)
+
+type /* error reasons */ (
+	NotFound struct {
+		ID string
+	}
+
+	InvalidValue struct {
+		Name     string
+		Value    string
+		Password string `json:"-"`
+	}
+
+	Conflict struct{}
+
+	Unmapped struct{}
+
+	InvalidStatus struct {
+		Status int
+	}
+
+	RegisteredSuccess struct{}
+)
+
+func (r InvalidStatus) HTTPStatus() int {
+	return r.Status
+}
+
+func (r NotFound) HTTPStatus() int {
+	return http.StatusNotFound
+}
+
+func (r *Conflict) HTTPStatus() int {
+	return http.StatusConflict
+}
+
+func init() {
+	errshttp.RegisterStatus[*InvalidValue](http.StatusBadRequest)
+	errshttp.RegisterStatus[RegisteredSuccess](http.StatusOK)
+}
+
+func TestStatusOf(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		assert.Equal(t, errshttp.StatusOf(errs.Ok()), http.StatusOK)
+	})
+
+	t.Run("reason implements StatusReason", func(t *testing.T) {
+		assert.Equal(t, errshttp.StatusOf(errs.New(NotFound{ID: "a"})), http.StatusNotFound)
+		assert.Equal(t, errshttp.StatusOf(errs.New(&NotFound{ID: "a"})), http.StatusNotFound)
+		assert.Equal(t, errshttp.StatusOf(errs.New(&Conflict{})), http.StatusConflict)
+	})
+
+	t.Run("reason type is registered", func(t *testing.T) {
+		assert.Equal(t, errshttp.StatusOf(errs.New(InvalidValue{})), http.StatusBadRequest)
+		assert.Equal(t, errshttp.StatusOf(errs.New(&InvalidValue{})), http.StatusBadRequest)
+	})
+
+	t.Run("reason type is not mapped", func(t *testing.T) {
+		assert.Equal(t, errshttp.StatusOf(errs.New(Unmapped{})), http.StatusInternalServerError)
+		assert.Equal(t, errshttp.StatusOf(errs.New("abc")), http.StatusInternalServerError)
+	})
+	t.Run("status code is not an error status code", func(t *testing.T) {
+		assert.Equal(t, errshttp.StatusOf(errs.New(InvalidStatus{Status: 0})), http.StatusInternalServerError)
+		assert.Equal(t, errshttp.StatusOf(errs.New(InvalidStatus{Status: 204})), http.StatusInternalServerError)
+		assert.Equal(t, errshttp.StatusOf(errs.New(InvalidStatus{Status: 399})), http.StatusInternalServerError)
+		assert.Equal(t, errshttp.StatusOf(errs.New(InvalidStatus{Status: 400})), http.StatusBadRequest)
+		assert.Equal(t, errshttp.StatusOf(errs.New(InvalidStatus{Status: 599})), 599)
+		assert.Equal(t, errshttp.StatusOf(errs.New(InvalidStatus{Status: 600})), http.StatusInternalServerError)
+		assert.Equal(t, errshttp.StatusOf(errs.New(RegisteredSuccess{})), http.StatusInternalServerError)
+	})
+}
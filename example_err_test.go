@@ -40,10 +40,10 @@ func ExampleNew() {
 	}, cause)
 	fmt.Printf("(4) %v\n", err)
 	// Output:
-	// (1) github.com/sttk/errs.Err {reason:github.com/sttk/errs_test.FailToDoSomething file:example_err_test.go line:20}
-	// (2) github.com/sttk/errs.Err {reason:github.com/sttk/errs_test.FailToDoWithParams{Param1:ABC Param2:123} file:example_err_test.go line:24}
-	// (3) github.com/sttk/errs.Err {reason:github.com/sttk/errs_test.FailToDoSomething file:example_err_test.go line:33 cause:Causal error}
-	// (4) github.com/sttk/errs.Err {reason:github.com/sttk/errs_test.FailToDoWithParams{Param1:ABC Param2:123} file:example_err_test.go line:37 cause:Causal error}
+	// (1) github.com/sttk/errs_test.FailToDoSomething
+	// (2) github.com/sttk/errs_test.FailToDoWithParams{Param1:ABC Param2:123}
+	// (3) github.com/sttk/errs_test.FailToDoSomething: Causal error
+	// (4) github.com/sttk/errs_test.FailToDoWithParams{Param1:ABC Param2:123}: Causal error
 }
 
 func ExampleOk() {
@@ -51,7 +51,7 @@ func ExampleOk() {
 	fmt.Printf("err = %v\n", err)
 	fmt.Printf("err.IsOk() = %v\n", err.IsOk())
 	// Output:
-	// err = github.com/sttk/errs.Err {}
+	// err = ok
 	// err.IsOk() = true
 }
 
@@ -140,17 +140,17 @@ func ExampleErr_Unwrap() {
 	cause1 := errors.New("Causal error 1")
 	cause2 := errors.New("Causal error 2")
 
-	err := errs.New(FailToDoSomething{}, cause1)
+	err := errs.New(FailToDoSomething{}, cause1, cause2)
 
-	fmt.Printf("err.Unwrap() = %v\n", err.Unwrap())
-	fmt.Printf("errors.Unwrap(err) = %v\n", errors.Unwrap(err))
+	fmt.Printf("err.Causes() = %v\n", err.Causes())
 	fmt.Printf("errors.Is(err, cause1) = %v\n", errors.Is(err, cause1))
 	fmt.Printf("errors.Is(err, cause2) = %v\n", errors.Is(err, cause2))
+	fmt.Printf("errors.Is(err, other) = %v\n", errors.Is(err, errors.New("Other")))
 	// Output:
-	// err.Unwrap() = Causal error 1
-	// errors.Unwrap(err) = Causal error 1
+	// err.Causes() = [Causal error 1 Causal error 2]
 	// errors.Is(err, cause1) = true
-	// errors.Is(err, cause2) = false
+	// errors.Is(err, cause2) = true
+	// errors.Is(err, other) = false
 }
 
 func ExampleErr_IfOkThen() {
@@ -188,3 +188,149 @@ func ExampleErr_File() {
 	// Output:
 	// file = example_err_test.go
 }
+
+func ExampleErr_Format() {
+	type FailToDoSomething struct{}
+	type FailToDoWithParams struct {
+		Param1 string
+		Param2 int
+	}
+
+	cause := errs.New(FailToDoSomething{})
+	err := errs.New(FailToDoWithParams{Param1: "ABC", Param2: 123}, cause)
+
+	fmt.Printf("%v\n", err)
+	fmt.Printf("%+v\n", err)
+	// Output:
+	// github.com/sttk/errs_test.FailToDoWithParams{Param1:ABC Param2:123}: github.com/sttk/errs_test.FailToDoSomething
+	// github.com/sttk/errs_test.FailToDoWithParams
+	//     Param1: ABC
+	//     Param2: 123
+	//     at example_err_test.go:200
+	//     cause: github.com/sttk/errs_test.FailToDoSomething
+	//         at example_err_test.go:199
+}
+
+func ExampleErr_AsError() {
+	type FailToDoSomething struct{}
+
+	doSomething := func(fail bool) error {
+		if fail {
+			return errs.New(FailToDoSomething{}).AsError()
+		}
+		return errs.Ok().AsError()
+	}
+
+	fmt.Printf("doSomething(false) == nil: %v\n", doSomething(false) == nil)
+	fmt.Printf("doSomething(true) == nil: %v\n", doSomething(true) == nil)
+	// Output:
+	// doSomething(false) == nil: true
+	// doSomething(true) == nil: false
+}
+
+func ExampleErr_OrElse() {
+	type FailToGetValue struct{}
+	type FailToGetDefault struct{}
+
+	err := errs.New(FailToGetValue{}).
+		IfNotOkThen(func(e errs.Err) {
+			fmt.Printf("log: %v\n", e)
+		}).
+		OrElse(func(e errs.Err) errs.Err {
+			return errs.New(FailToGetDefault{}, e)
+		}).
+		Finally(func() {
+			fmt.Println("finally")
+		})
+	fmt.Printf("%v\n", err)
+	// Output:
+	// log: github.com/sttk/errs_test.FailToGetValue
+	// finally
+	// github.com/sttk/errs_test.FailToGetDefault: github.com/sttk/errs_test.FailToGetValue
+}
+
+func ExampleErr_MapReason() {
+	type NotFound struct{ Key string }
+	type InvalidConfig struct{ Key string }
+
+	err := errs.New(NotFound{Key: "port"}).MapReason(func(r any) any {
+		if nf, ok := r.(NotFound); ok {
+			return InvalidConfig{Key: nf.Key}
+		}
+		return r
+	})
+	fmt.Printf("%v\n", err)
+	// Output:
+	// github.com/sttk/errs_test.InvalidConfig{Key:port}
+}
+
+func ExampleRecover() {
+	type NotFound struct{ Key string }
+
+	err := errs.Recover(errs.New(NotFound{Key: "port"}), func(r NotFound) errs.Err {
+		fmt.Printf("use the default value for %s\n", r.Key)
+		return errs.Ok()
+	})
+	fmt.Printf("err.IsOk() = %v\n", err.IsOk())
+	// Output:
+	// use the default value for port
+	// err.IsOk() = true
+}
+
+func ExampleMatch() {
+	type NotFound struct{ Key string }
+	type Timeout struct{ Seconds int }
+
+	describe := func(err errs.Err) string {
+		return errs.Match(err,
+			errs.Case(func(r NotFound) string { return "not found: " + r.Key }),
+			errs.Case(func(r *Timeout) string { return fmt.Sprintf("timeout: %ds", r.Seconds) }),
+			errs.Default(func(e errs.Err) string { return "unknown" }),
+		)
+	}
+
+	fmt.Println(describe(errs.New(NotFound{Key: "port"})))
+	fmt.Println(describe(errs.New(Timeout{Seconds: 3})))
+	fmt.Println(describe(errs.New("other")))
+	// Output:
+	// not found: port
+	// timeout: 3s
+	// unknown
+}
+
+func ExampleRecoverPanic() {
+	doSomething := func() (err errs.Err) {
+		defer errs.RecoverPanic(&err)
+		panic("boom")
+	}
+
+	err := doSomething()
+	fmt.Printf("%v\n", err)
+	// Output:
+	// github.com/sttk/errs.Panicked{Value:boom}
+}
+
+func ExampleGroup() {
+	type FailToFetch struct{ URL string }
+
+	var g errs.Group
+	for _, url := range []string{"a", "b", "c"} {
+		url := url
+		g.GoLabeled(url, func() errs.Err {
+			if url == "b" {
+				return errs.New(FailToFetch{URL: url})
+			}
+			return errs.Ok()
+		})
+	}
+
+	err := g.Wait()
+	fmt.Printf("%v\n", err.Reason())
+	for _, cause := range err.Causes() {
+		r, _ := errs.ReasonOf[errs.TaskFailed](cause)
+		fmt.Printf("%s: %v\n", r.Label, cause.(errs.Err).Cause())
+	}
+	// Output:
+	// {3 1}
+	// b: github.com/sttk/errs_test.FailToFetch{URL:b}
+}
@@ -0,0 +1,184 @@
+// Copyright (C) 2025-2026 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package errs
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const detailIndent = "    "
+
+// Format implements fmt.Formatter, and writes the Err in the form specified by the verb.
+//
+//	%s    the same string as the Error method returns.
+//	%q    a double-quoted string of the Error method's result.
+//	%v    a short message consisting of the reason and the messages of the cause chain.
+//	%+v   a multi-line detailed view with the reason fields, the location, the stack trace
+//	      (if captured), and each level of the cause chain indented.
+//	%#v   a Go-syntax representation of this Err.
+func (e Err) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('#') {
+			writeString(s, e.goString())
+		} else if s.Flag('+') {
+			writeString(s, e.detail())
+		} else {
+			writeString(s, e.shortMessage())
+		}
+	case 's':
+		writeString(s, e.Error())
+	case 'q':
+		writeString(s, strconv.Quote(e.Error()))
+	default:
+		fmt.Fprintf(s, "%%!%c(errs.Err=%s)", verb, e.Error())
+	}
+}
+
+func writeString(s fmt.State, str string) {
+	_, _ = s.Write([]byte(str))
+}
+
+func (e Err) shortMessage() string {
+	if e.reason == nil { // Ok
+		return "ok"
+	}
+	msg := reasonString(e.reason)
+	if e.causes != nil {
+		msg += ": [" + joinErrors(*e.causes, ", ", "%v") + "]"
+	} else if e.cause != nil {
+		msg += ": " + fmt.Sprintf("%v", e.cause)
+	}
+	return msg
+}
+
+func (e Err) goString() string {
+	if e.reason == nil && e.cause == nil { // Ok
+		return "errs.Err{}"
+	}
+	if e.cause == nil {
+		return fmt.Sprintf("errs.Err{reason:%#v, file:%q, line:%d}", e.reason, e.file, e.line)
+	}
+	if e.causes == nil {
+		return fmt.Sprintf("errs.Err{reason:%#v, file:%q, line:%d, cause:%#v}",
+			e.reason, e.file, e.line, e.cause)
+	}
+	return fmt.Sprintf("errs.Err{reason:%#v, file:%q, line:%d, causes:[]error{%s}}",
+		e.reason, e.file, e.line, joinErrors(*e.causes, ", ", "%#v"))
+}
+
+func (e Err) detail() string {
+	if e.reason == nil { // Ok
+		return "ok"
+	}
+
+	var b strings.Builder
+
+	v := reflect.ValueOf(e.reason)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Struct {
+		t := v.Type()
+		b.WriteString(structTypeName(t))
+		for i := 0; i < t.NumField(); i++ {
+			fmt.Fprintf(&b, "\n%s%s: %+v", detailIndent, t.Field(i).Name, v.Field(i))
+		}
+	} else {
+		b.WriteString(reasonString(e.reason))
+	}
+
+	fmt.Fprintf(&b, "\n%sat %s:%d", detailIndent, e.file, e.line)
+
+	if frames := e.StackTrace(); len(frames) > 0 {
+		fmt.Fprintf(&b, "\n%sstack:", detailIndent)
+		for _, f := range frames {
+			fmt.Fprintf(&b, "\n%s%s%s", detailIndent, detailIndent, f.Function)
+			fmt.Fprintf(&b, "\n%s%s%s%s:%d", detailIndent, detailIndent, detailIndent, f.File, f.Line)
+		}
+	}
+
+	for _, c := range e.Causes() {
+		var cause string
+		if ce, ok := c.(Err); ok {
+			cause = ce.detail()
+		} else {
+			cause = fmt.Sprintf("%+v", c)
+		}
+		fmt.Fprintf(&b, "\n%scause: %s", detailIndent, indentLines(cause, detailIndent))
+	}
+
+	return b.String()
+}
+
+func indentLines(s, indent string) string {
+	return strings.ReplaceAll(s, "\n", "\n"+indent)
+}
+
+// Format implements fmt.Formatter, and writes the BatchErr in the form specified by the verb.
+//
+//	%s    the same string as the Error method returns.
+//	%q    a double-quoted string of the Error method's result.
+//	%v    a short message consisting of the numbers of the items and the short messages of the
+//	      failed items.
+//	%+v   a multi-line detailed view with the detailed view of each failed item indented.
+//	%#v   a Go-syntax representation of this BatchErr.
+func (b *BatchErr) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('#') {
+			writeString(s, b.goString())
+		} else if s.Flag('+') {
+			writeString(s, b.detail())
+		} else {
+			writeString(s, b.shortMessage())
+		}
+	case 's':
+		writeString(s, b.Error())
+	case 'q':
+		writeString(s, strconv.Quote(b.Error()))
+	default:
+		fmt.Fprintf(s, "%%!%c(*errs.BatchErr=%s)", verb, b.Error())
+	}
+}
+
+func (b *BatchErr) shortMessage() string {
+	items := b.Items()
+	msg := fmt.Sprintf("%d of %d items failed", len(items), b.Total())
+	if len(items) == 0 {
+		return msg
+	}
+	parts := make([]string, len(items))
+	for i, it := range items {
+		parts[i] = it.Key() + ": " + it.Err.shortMessage()
+	}
+	return msg + ": [" + strings.Join(parts, ", ") + "]"
+}
+
+func (b *BatchErr) goString() string {
+	items := b.Items()
+	parts := make([]string, len(items))
+	for i, it := range items {
+		parts[i] = fmt.Sprintf("{Index:%d, ID:%q, Err:%#v}", it.Index, it.ID, it.Err)
+	}
+	return fmt.Sprintf("&errs.BatchErr{total:%d, items:[]errs.BatchItem{%s}}",
+		b.Total(), strings.Join(parts, ", "))
+}
+
+func (b *BatchErr) detail() string {
+	items := b.Items()
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "github.com/sttk/errs.BatchErr\n%sfailed: %d\n%stotal: %d",
+		detailIndent, len(items), detailIndent, b.Total())
+	for _, it := range items {
+		fmt.Fprintf(&sb, "\n%sitem %s: %s", detailIndent, it.Key(),
+			indentLines(it.Err.detail(), detailIndent))
+	}
+	return sb.String()
+}
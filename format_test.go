@@ -0,0 +1,143 @@
+package errs_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/errs"
+)
+
+func TestErr_Format(t *testing.T) {
+	t.Run("%v", func(t *testing.T) {
+		t.Run("ok", func(t *testing.T) {
+			err := errs.Ok()
+			assert.Equal(t, fmt.Sprintf("%v", err), "ok")
+		})
+
+		t.Run("reason is a value", func(t *testing.T) {
+			err := errs.New(InvalidValue{Name: "foo", Value: "abc"})
+			assert.Equal(t, fmt.Sprintf("%v", err), "github.com/sttk/errs_test.InvalidValue{Name:foo Value:abc}")
+		})
+
+		t.Run("reason is a pointer", func(t *testing.T) {
+			err := errs.New(&InvalidValue{Name: "foo", Value: "abc"})
+			assert.Equal(t, fmt.Sprintf("%v", err), "github.com/sttk/errs_test.InvalidValue{Name:foo Value:abc}")
+		})
+
+		t.Run("reason is a string", func(t *testing.T) {
+			err := errs.New("abc")
+			assert.Equal(t, fmt.Sprintf("%v", err), "abc")
+		})
+
+		t.Run("with cause chain", func(t *testing.T) {
+			cause := errs.New(FailToGetValue{Name: "foo"}, errors.New("def"))
+			err := errs.New(InvalidValue{Name: "foo", Value: "abc"}, cause)
+			assert.Equal(t, fmt.Sprintf("%v", err), "github.com/sttk/errs_test.InvalidValue{Name:foo Value:abc}: github.com/sttk/errs_test.FailToGetValue{Name:foo}: def")
+		})
+	})
+
+	t.Run("%+v", func(t *testing.T) {
+		t.Run("ok", func(t *testing.T) {
+			err := errs.Ok()
+			assert.Equal(t, fmt.Sprintf("%+v", err), "ok")
+		})
+
+		t.Run("reason is a struct", func(t *testing.T) {
+			err := errs.New(&InvalidValue{Name: "foo", Value: "abc"})
+			assert.Equal(t, fmt.Sprintf("%+v", err), strings.Join([]string{
+				"github.com/sttk/errs_test.InvalidValue",
+				"    Name: foo",
+				"    Value: abc",
+				"    at format_test.go:49",
+			}, "\n"))
+		})
+
+		t.Run("reason is not a struct", func(t *testing.T) {
+			err := errs.New(123)
+			assert.Equal(t, fmt.Sprintf("%+v", err), strings.Join([]string{
+				"123",
+				"    at format_test.go:59",
+			}, "\n"))
+		})
+
+		t.Run("with cause chain", func(t *testing.T) {
+			cause := errs.New(FailToGetValue{Name: "foo"}, errors.New("def"))
+			err := errs.New(InvalidValue{Name: "foo", Value: "abc"}, cause)
+			assert.Equal(t, fmt.Sprintf("%+v", err), strings.Join([]string{
+				"github.com/sttk/errs_test.InvalidValue",
+				"    Name: foo",
+				"    Value: abc",
+				"    at format_test.go:68",
+				"    cause: github.com/sttk/errs_test.FailToGetValue",
+				"        Name: foo",
+				"        at format_test.go:67",
+				"        cause: def",
+			}, "\n"))
+		})
+
+		t.Run("with stack trace", func(t *testing.T) {
+			errs.SetStackTraceDepth(1)
+			defer errs.SetStackTraceDepth(0)
+
+			err := errs.New(FailToGetValue{Name: "foo"})
+			lines := strings.Split(fmt.Sprintf("%+v", err), "\n")
+			assert.Len(t, lines, 6)
+			assert.Equal(t, lines[0], "github.com/sttk/errs_test.FailToGetValue")
+			assert.Equal(t, lines[1], "    Name: foo")
+			assert.Equal(t, lines[2], "    at format_test.go:85")
+			assert.Equal(t, lines[3], "    stack:")
+			assert.True(t, strings.HasPrefix(lines[4], "        github.com/sttk/errs_test.TestErr_Format."))
+			assert.True(t, strings.HasPrefix(lines[5], "            /"))
+			assert.True(t, strings.HasSuffix(lines[5], "/format_test.go:85"))
+		})
+	})
+
+	t.Run("%#v", func(t *testing.T) {
+		t.Run("ok", func(t *testing.T) {
+			err := errs.Ok()
+			assert.Equal(t, fmt.Sprintf("%#v", err), "errs.Err{}")
+		})
+
+		t.Run("without cause", func(t *testing.T) {
+			err := errs.New(InvalidValue{Name: "foo", Value: "abc"})
+			assert.Equal(t, fmt.Sprintf("%#v", err), `errs.Err{reason:errs_test.InvalidValue{Name:"foo", Value:"abc"}, file:"format_test.go", line:105}`)
+		})
+
+		t.Run("with cause", func(t *testing.T) {
+			cause := errs.New(FailToGetValue{Name: "foo"})
+			err := errs.New(&InvalidValue{Name: "foo", Value: "abc"}, cause)
+			assert.Equal(t, fmt.Sprintf("%#v", err), `errs.Err{reason:&errs_test.InvalidValue{Name:"foo", Value:"abc"}, file:"format_test.go", line:111, cause:errs.Err{reason:errs_test.FailToGetValue{Name:"foo"}, file:"format_test.go", line:110}}`)
+		})
+	})
+
+	t.Run("%s and %q", func(t *testing.T) {
+		err := errs.New(FailToGetValue{Name: "foo"})
+		assert.Equal(t, fmt.Sprintf("%s", err), "github.com/sttk/errs.Err {reason:github.com/sttk/errs_test.FailToGetValue{Name:foo} file:format_test.go line:117}")
+		assert.Equal(t, fmt.Sprintf("%q", err), `"github.com/sttk/errs.Err {reason:github.com/sttk/errs_test.FailToGetValue{Name:foo} file:format_test.go line:117}"`)
+	})
+
+	t.Run("unsupported verb", func(t *testing.T) {
+		err := errs.New(FailToGetValue{Name: "foo"})
+		assert.Equal(t, fmt.Sprintf("%d", err), "%!d(errs.Err=github.com/sttk/errs.Err {reason:github.com/sttk/errs_test.FailToGetValue{Name:foo} file:format_test.go line:123})")
+	})
+}
+
+func TestErr_Format_multipleCauses(t *testing.T) {
+	cause1 := errors.New("def")
+	cause2 := errs.New(FailToGetValue{Name: "foo"})
+	err := errs.New(InvalidValue{Name: "foo", Value: "abc"}, cause1, cause2)
+
+	assert.Equal(t, fmt.Sprintf("%v", err), "github.com/sttk/errs_test.InvalidValue{Name:foo Value:abc}: [def, github.com/sttk/errs_test.FailToGetValue{Name:foo}]")
+	assert.Equal(t, fmt.Sprintf("%+v", err), `github.com/sttk/errs_test.InvalidValue
+    Name: foo
+    Value: abc
+    at format_test.go:131
+    cause: def
+    cause: github.com/sttk/errs_test.FailToGetValue
+        Name: foo
+        at format_test.go:130`)
+	assert.Equal(t, fmt.Sprintf("%#v", err), `errs.Err{reason:errs_test.InvalidValue{Name:"foo", Value:"abc"}, file:"format_test.go", line:131, causes:[]error{&errors.errorString{s:"def"}, errs.Err{reason:errs_test.FailToGetValue{Name:"foo"}, file:"format_test.go", line:130}}}`)
+}
@@ -0,0 +1,172 @@
+// Copyright (C) 2025-2026 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package errs
+
+import (
+	"context"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+type /* error reasons */ (
+	// Aggregated is the reason of an Err which Group.Wait returns when some tasks have failed.
+	// Total is the number of the tasks, and Failed is the number of the failed tasks.
+	// The causes of the Err are the Err(s) whose reasons are TaskFailed, one for each failed task,
+	// in the order in which the tasks were started.
+	Aggregated struct {
+		Total  int
+		Failed int
+	}
+
+	// TaskFailed is the reason of an Err which represents a failed task of a Group.
+	// Index is the index of the task in the order in which the tasks were started, and Label is the
+	// label given with Group.GoLabeled, or is empty.
+	// The cause of the Err is the Err returned by the task, and its location is where the task was
+	// started.
+	TaskFailed struct {
+		Index int
+		Label string
+	}
+)
+
+// GroupMode is the type of the modes of a Group.
+type GroupMode int
+
+const (
+	// CollectAll is the mode in which a Group runs all the tasks regardless of failures.
+	CollectAll GroupMode = iota
+
+	// CancelOnFirstErr is the mode in which a Group cancels its context when a task fails first.
+	CancelOnFirstErr
+)
+
+// Group is the struct which runs tasks returning Err(s) concurrently and collects their failures.
+// Unlike errgroup.Group, a Group retains the failures of all the tasks, and Wait returns an Err
+// whose reason is Aggregated and whose causes represent the failed tasks.
+//
+// A zero Group is valid, runs in the CollectAll mode, has no limit on the number of active tasks,
+// and does not cancel on failure.
+// A panic in a task is recovered and is regarded as a failure of the task, like Safe.
+type Group struct {
+	cancel context.CancelFunc
+	mode   GroupMode
+	sem    chan struct{}
+	wg     sync.WaitGroup
+
+	mu       sync.Mutex
+	total    int
+	failures []taskFailure
+}
+
+type taskFailure struct {
+	index int
+	err   Err
+}
+
+// NewGroup creates a Group with the specified mode, and a new context derived from the specified
+// context.
+// The derived context is canceled when Wait returns, or, in the CancelOnFirstErr mode, when a task
+// fails first.
+func NewGroup(ctx context.Context, mode GroupMode) (*Group, context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Group{cancel: cancel, mode: mode}, ctx
+}
+
+// SetLimit limits the number of active tasks in this Group to at most n.
+// If n is zero or negative, the number is not limited.
+// When the limit is reached, Go and GoLabeled block until a task finishes.
+// This method must not be called while tasks are active.
+func (g *Group) SetLimit(n int) {
+	if n <= 0 {
+		g.sem = nil
+		return
+	}
+	g.sem = make(chan struct{}, n)
+}
+
+// Go runs the specified function in a new goroutine as a task of this Group.
+func (g *Group) Go(fn func() Err) {
+	g.start("", fn, 1)
+}
+
+// GoLabeled runs the specified function in a new goroutine as a task of this Group with a label,
+// which identifies the task in the Err returned by Wait.
+func (g *Group) GoLabeled(label string, fn func() Err) {
+	g.start(label, fn, 1)
+}
+
+func (g *Group) start(label string, fn func() Err, skip int) {
+	if g.sem != nil {
+		g.sem <- struct{}{}
+	}
+
+	g.mu.Lock()
+	index := g.total
+	g.total++
+	g.mu.Unlock()
+
+	var e Err
+	e.reason = TaskFailed{Index: index, Label: label}
+	_, file, line, ok := runtime.Caller(skip + 1)
+	if ok {
+		e.file = filepath.Base(file)
+		e.line = line
+	}
+
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		if g.sem != nil {
+			defer func() { <-g.sem }()
+		}
+
+		if err := Safe(fn); err.IsNotOk() {
+			e.cause = err
+			g.fail(index, e)
+		}
+	}()
+}
+
+func (g *Group) fail(index int, e Err) {
+	g.mu.Lock()
+	g.failures = append(g.failures, taskFailure{index: index, err: e})
+	first := len(g.failures) == 1
+	g.mu.Unlock()
+
+	if first && g.mode == CancelOnFirstErr && g.cancel != nil {
+		g.cancel()
+	}
+}
+
+// Wait blocks until all the tasks of this Group have finished.
+// If no task has failed, this method returns Ok. Otherwise, this method returns an Err whose
+// reason is Aggregated, and whose causes are the Err(s) whose reasons are TaskFailed, one for each
+// failed task, in the order in which the tasks were started.
+// The Err(s) with TaskFailed reasons are not notified to the error handlers, since the Err(s)
+// returned by the tasks have been notified.
+func (g *Group) Wait() Err {
+	g.wg.Wait()
+	if g.cancel != nil {
+		g.cancel()
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if len(g.failures) == 0 {
+		return Ok()
+	}
+
+	sort.Slice(g.failures, func(i, j int) bool {
+		return g.failures[i].index < g.failures[j].index
+	})
+	causes := make([]error, len(g.failures))
+	for i, f := range g.failures {
+		causes[i] = f.err
+	}
+	return newErr(1, Aggregated{Total: g.total, Failed: len(g.failures)}, causes)
+}
@@ -0,0 +1,152 @@
+package errs_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/errs"
+)
+
+func TestGroup(t *testing.T) {
+	t.Run("zero group with no failure", func(t *testing.T) {
+		var g errs.Group
+		var count int32
+		for i := 0; i < 5; i++ {
+			g.Go(func() errs.Err {
+				atomic.AddInt32(&count, 1)
+				return errs.Ok()
+			})
+		}
+		err := g.Wait()
+		assert.True(t, err.IsOk())
+		assert.Equal(t, atomic.LoadInt32(&count), int32(5))
+	})
+
+	t.Run("no task", func(t *testing.T) {
+		g, _ := errs.NewGroup(context.Background(), errs.CollectAll)
+		assert.True(t, g.Wait().IsOk())
+	})
+
+	t.Run("collect all", func(t *testing.T) {
+		g, ctx := errs.NewGroup(context.Background(), errs.CollectAll)
+
+		g.Go(func() errs.Err {
+			time.Sleep(10 * time.Millisecond)
+			return errs.New(InvalidValue{Name: "a"})
+		})
+		g.Go(func() errs.Err {
+			return errs.Ok()
+		})
+		g.GoLabeled("c", func() errs.Err {
+			return errs.New(FailToGetValue{Name: "c"})
+		})
+
+		err := g.Wait()
+		assert.Equal(t, err.Reason(), errs.Aggregated{Total: 3, Failed: 2})
+		assert.Equal(t, err.File(), "group_test.go")
+		assert.Equal(t, err.Line(), 48)
+		assert.True(t, errors.Is(ctx.Err(), context.Canceled))
+
+		causes := err.Causes()
+		assert.Len(t, causes, 2)
+
+		c0 := causes[0].(errs.Err)
+		assert.Equal(t, c0.Reason(), errs.TaskFailed{Index: 0, Label: ""})
+		assert.Equal(t, c0.File(), "group_test.go")
+		assert.Equal(t, c0.Line(), 37)
+		assert.Equal(t, c0.Cause().(errs.Err).Reason(), InvalidValue{Name: "a"})
+
+		c1 := causes[1].(errs.Err)
+		assert.Equal(t, c1.Reason(), errs.TaskFailed{Index: 2, Label: "c"})
+		assert.Equal(t, c1.Line(), 44)
+		assert.Equal(t, c1.Cause().(errs.Err).Reason(), FailToGetValue{Name: "c"})
+
+		assert.True(t, errs.HasReason[InvalidValue](err))
+		assert.True(t, errs.HasReason[FailToGetValue](err))
+		r, ok := errs.ReasonOf[errs.TaskFailed](err)
+		assert.True(t, ok)
+		assert.Equal(t, r.Index, 0)
+	})
+
+	t.Run("collect all does not cancel", func(t *testing.T) {
+		g, ctx := errs.NewGroup(context.Background(), errs.CollectAll)
+
+		g.Go(func() errs.Err {
+			return errs.New(InvalidValue{Name: "a"})
+		})
+		g.Go(func() errs.Err {
+			select {
+			case <-ctx.Done():
+				return errs.New(FailToGetValue{Name: "canceled"})
+			case <-time.After(20 * time.Millisecond):
+				return errs.Ok()
+			}
+		})
+
+		err := g.Wait()
+		assert.Equal(t, err.Reason(), errs.Aggregated{Total: 2, Failed: 1})
+	})
+
+	t.Run("cancel on first error", func(t *testing.T) {
+		g, ctx := errs.NewGroup(context.Background(), errs.CancelOnFirstErr)
+
+		g.Go(func() errs.Err {
+			return errs.New(InvalidValue{Name: "a"})
+		})
+		g.GoLabeled("waiter", func() errs.Err {
+			select {
+			case <-ctx.Done():
+				return errs.New(FailToGetValue{Name: "canceled"}, ctx.Err())
+			case <-time.After(10 * time.Second):
+				return errs.Ok()
+			}
+		})
+
+		err := g.Wait()
+		assert.Equal(t, err.Reason(), errs.Aggregated{Total: 2, Failed: 2})
+		causes := err.Causes()
+		assert.Equal(t, causes[0].(errs.Err).Cause().(errs.Err).Reason(), InvalidValue{Name: "a"})
+		assert.Equal(t, causes[1].(errs.Err).Reason(), errs.TaskFailed{Index: 1, Label: "waiter"})
+		assert.True(t, errors.Is(err, context.Canceled))
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		g, _ := errs.NewGroup(context.Background(), errs.CollectAll)
+		g.SetLimit(2)
+
+		var active, maxActive int32
+		for i := 0; i < 10; i++ {
+			g.Go(func() errs.Err {
+				n := atomic.AddInt32(&active, 1)
+				for {
+					m := atomic.LoadInt32(&maxActive)
+					if n <= m || atomic.CompareAndSwapInt32(&maxActive, m, n) {
+						break
+					}
+				}
+				time.Sleep(time.Millisecond)
+				atomic.AddInt32(&active, -1)
+				return errs.Ok()
+			})
+		}
+		assert.True(t, g.Wait().IsOk())
+		assert.True(t, atomic.LoadInt32(&maxActive) <= 2)
+	})
+
+	t.Run("panic in a task", func(t *testing.T) {
+		var g errs.Group
+		g.Go(func() errs.Err {
+			panic("boom")
+		})
+
+		err := g.Wait()
+		assert.Equal(t, err.Reason(), errs.Aggregated{Total: 1, Failed: 1})
+		r, ok := errs.ReasonOf[errs.Panicked](err)
+		assert.True(t, ok)
+		assert.Equal(t, r.Value, "boom")
+	})
+}
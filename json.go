@@ -0,0 +1,341 @@
+// Copyright (C) 2025-2026 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package errs
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// UnknownReason is the reason set to an Err which is unmarshalled from JSON when the type of the
+// original reason is not registered with RegisterReason.
+// Type is the fully qualified type name of the original reason, and Value is the JSON text of
+// the original reason.
+//
+// When an Err with this reason is marshalled to JSON again, the original type name and value are
+// output as they are.
+type UnknownReason struct {
+	Type  string
+	Value string
+}
+
+var (
+	reasonTypeRegistry = map[string]reflect.Type{}
+	reasonTypeMutex    sync.RWMutex
+)
+
+func init() {
+	registerReasonType(reflect.TypeOf(""))
+	registerReasonType(reflect.TypeOf(false))
+	registerReasonType(reflect.TypeOf(int(0)))
+	registerReasonType(reflect.TypeOf(int8(0)))
+	registerReasonType(reflect.TypeOf(int16(0)))
+	registerReasonType(reflect.TypeOf(int32(0)))
+	registerReasonType(reflect.TypeOf(int64(0)))
+	registerReasonType(reflect.TypeOf(uint(0)))
+	registerReasonType(reflect.TypeOf(uint8(0)))
+	registerReasonType(reflect.TypeOf(uint16(0)))
+	registerReasonType(reflect.TypeOf(uint32(0)))
+	registerReasonType(reflect.TypeOf(uint64(0)))
+	registerReasonType(reflect.TypeOf(float32(0)))
+	registerReasonType(reflect.TypeOf(float64(0)))
+	registerReasonType(reflect.TypeOf(Wrapped{}))
+	registerReasonType(reflect.TypeOf(Panicked{}))
+	registerReasonType(reflect.TypeOf(Aggregated{}))
+	registerReasonType(reflect.TypeOf(TaskFailed{}))
+}
+
+// RegisterReason registers the type parameter T as a reason type which can be reconstructed when
+// an Err is unmarshalled from JSON.
+// Registering either T or *T enables both of the value and pointer forms of the reason.
+// Basic types, such as string, bool and numeric types, and the reasons of this package, such as
+// Wrapped and Panicked, are registered in advance.
+//
+// This function is typically called in an init function of the package which defines the
+// reason type.
+func RegisterReason[T any]() {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	registerReasonType(t)
+}
+
+func registerReasonType(t reflect.Type) {
+	reasonTypeMutex.Lock()
+	defer reasonTypeMutex.Unlock()
+	reasonTypeRegistry[reasonTypeName(t)] = t
+}
+
+func lookupReasonType(name string) (reflect.Type, bool) {
+	reasonTypeMutex.RLock()
+	defer reasonTypeMutex.RUnlock()
+	t, ok := reasonTypeRegistry[name]
+	return t, ok
+}
+
+func reasonTypeName(t reflect.Type) string {
+	if t.Kind() == reflect.Ptr {
+		return "*" + reasonTypeName(t.Elem())
+	}
+	if len(t.Name()) > 0 {
+		return structTypeName(t)
+	}
+	return t.String()
+}
+
+type errJSON struct {
+	ReasonType string            `json:"reason_type,omitempty"`
+	Reason     json.RawMessage   `json:"reason,omitempty"`
+	File       string            `json:"file,omitempty"`
+	Line       int               `json:"line,omitempty"`
+	Cause      json.RawMessage   `json:"cause,omitempty"`
+	Causes     []json.RawMessage `json:"causes,omitempty"`
+	Message    string            `json:"message,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler, and returns a JSON document which consists of the fully
+// qualified type name of the reason, the reason's fields, the file, the line, and the cause
+// chain, as follows:
+//
+//	{
+//	  "reason_type": "github.com/foo/bar.InvalidValue",
+//	  "reason": {"Name": "abc", "Value": "def"},
+//	  "file": "bar.go",
+//	  "line": 123,
+//	  "cause": {
+//	    "reason_type": "github.com/foo/bar.FailToGetValue",
+//	    "reason": {"Name": "abc"},
+//	    "file": "bar.go",
+//	    "line": 100,
+//	    "cause": {"message": "an error which is not Err"}
+//	  }
+//	}
+//
+// A cause which is an Err is output in the same form recursively, and another cause is output
+// with only its message.
+// If the Err has multiple causes, they are output as an array with the key "causes" instead of
+// "cause".
+// An Err which indicates no error is output as an empty object.
+//
+// The reason is marshalled with encoding/json, so only the exported fields of a struct reason are
+// output.
+func (e Err) MarshalJSON() ([]byte, error) {
+	doc, err := e.toJSON()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(doc)
+}
+
+func (e Err) toJSON() (errJSON, error) {
+	var doc errJSON
+	if e.reason == nil { // Ok
+		return doc, nil
+	}
+
+	if r, ok := e.reason.(UnknownReason); ok {
+		doc.ReasonType = r.Type
+		doc.Reason = json.RawMessage(r.Value)
+	} else {
+		doc.ReasonType = reasonTypeName(reflect.TypeOf(e.reason))
+		b, err := json.Marshal(e.reason)
+		if err != nil {
+			return doc, err
+		}
+		doc.Reason = b
+	}
+
+	doc.File = e.file
+	doc.Line = e.line
+
+	if e.causes != nil {
+		for _, c := range *e.causes {
+			b, err := marshalCause(c)
+			if err != nil {
+				return doc, err
+			}
+			doc.Causes = append(doc.Causes, b)
+		}
+	} else if e.cause != nil {
+		b, err := marshalCause(e.cause)
+		if err != nil {
+			return doc, err
+		}
+		doc.Cause = b
+	}
+
+	return doc, nil
+}
+
+func marshalCause(cause error) (json.RawMessage, error) {
+	if c, ok := cause.(Err); ok && c.IsNotOk() {
+		return json.Marshal(c)
+	}
+	return json.Marshal(errJSON{Message: cause.Error()})
+}
+
+// UnmarshalJSON implements json.Unmarshaler, and reconstructs an Err from a JSON document output
+// by MarshalJSON.
+// If the type of the reason is registered with RegisterReason, the reason is reconstructed as a
+// value of that type. Otherwise, the reason becomes an UnknownReason.
+// A cause which is not an Err is reconstructed as an error which has only the original message.
+func (e *Err) UnmarshalJSON(data []byte) error {
+	var doc errJSON
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	return e.fromJSON(doc)
+}
+
+func (e *Err) fromJSON(doc errJSON) error {
+	*e = Err{}
+	if len(doc.ReasonType) == 0 { // Ok
+		return nil
+	}
+
+	reason, err := unmarshalReason(doc.ReasonType, doc.Reason)
+	if err != nil {
+		return err
+	}
+	e.reason = reason
+	e.file = doc.File
+	e.line = doc.Line
+
+	if len(doc.Cause) > 0 {
+		cause, err := unmarshalCause(doc.Cause)
+		if err != nil {
+			return err
+		}
+		e.cause = cause
+	}
+
+	if len(doc.Causes) > 0 {
+		causes := make([]error, len(doc.Causes))
+		for i, data := range doc.Causes {
+			cause, err := unmarshalCause(data)
+			if err != nil {
+				return err
+			}
+			causes[i] = cause
+		}
+		e.setCauses(causes)
+	}
+
+	return nil
+}
+
+func unmarshalReason(typeName string, data json.RawMessage) (any, error) {
+	name := strings.TrimPrefix(typeName, "*")
+	isPtr := len(name) != len(typeName)
+
+	t, ok := lookupReasonType(name)
+	if !ok {
+		value := "null"
+		if len(data) > 0 {
+			value = string(data)
+		}
+		return UnknownReason{Type: typeName, Value: value}, nil
+	}
+
+	v := reflect.New(t)
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, v.Interface()); err != nil {
+			return nil, err
+		}
+	}
+	if isPtr {
+		return v.Interface(), nil
+	}
+	return v.Elem().Interface(), nil
+}
+
+func unmarshalCause(data json.RawMessage) (error, error) {
+	var doc errJSON
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.ReasonType) == 0 {
+		return errors.New(doc.Message), nil
+	}
+	var c Err
+	if err := c.fromJSON(doc); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+type batchErrJSON struct {
+	Total  int             `json:"total"`
+	Failed int             `json:"failed"`
+	Items  []batchItemJSON `json:"items,omitempty"`
+}
+
+type batchItemJSON struct {
+	Index *int    `json:"index,omitempty"`
+	ID    string  `json:"id,omitempty"`
+	Err   errJSON `json:"err"`
+}
+
+// MarshalJSON implements json.Marshaler, and returns a JSON document which consists of the
+// numbers of all the items and the failed items, and the failed items each of which has the index
+// or the ID and the Err in the form of Err.MarshalJSON, as follows:
+//
+//	{
+//	  "total": 500,
+//	  "failed": 2,
+//	  "items": [
+//	    {"index": 3, "err": {"reason_type": "github.com/foo/bar.InvalidValue", ...}},
+//	    {"id": "abc", "err": {"reason_type": "github.com/foo/bar.Conflict", ...}}
+//	  ]
+//	}
+func (b *BatchErr) MarshalJSON() ([]byte, error) {
+	items := b.Items()
+	doc := batchErrJSON{Total: b.Total(), Failed: len(items)}
+	for _, it := range items {
+		e, err := it.Err.toJSON()
+		if err != nil {
+			return nil, err
+		}
+		item := batchItemJSON{ID: it.ID, Err: e}
+		if it.Index >= 0 {
+			index := it.Index
+			item.Index = &index
+		}
+		doc.Items = append(doc.Items, item)
+	}
+	return json.Marshal(doc)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, and reconstructs a BatchErr from a JSON document
+// output by MarshalJSON.
+// The Err of each item is reconstructed in the same way as Err.UnmarshalJSON.
+func (b *BatchErr) UnmarshalJSON(data []byte) error {
+	var doc batchErrJSON
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	items := make([]BatchItem, 0, len(doc.Items))
+	for _, item := range doc.Items {
+		it := BatchItem{Index: -1, ID: item.ID}
+		if item.Index != nil {
+			it.Index = *item.Index
+		}
+		if err := it.Err.fromJSON(item.Err); err != nil {
+			return err
+		}
+		items = append(items, it)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.total = doc.Total
+	b.items = items
+	return nil
+}
@@ -0,0 +1,201 @@
+package errs_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/errs"
+)
+
+type /* error reasons */ (
+	RegisteredReason struct {
+		Name  string
+		Count int
+	}
+
+	UnregisteredReason struct {
+		Name string
+	}
+)
+
+func init() {
+	errs.RegisterReason[RegisteredReason]()
+	errs.RegisterReason[*FailToGetValue]()
+}
+
+func TestErr_MarshalJSON(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		b, err := json.Marshal(errs.Ok())
+		assert.Nil(t, err)
+		assert.Equal(t, string(b), `{}`)
+	})
+
+	t.Run("reason is a value", func(t *testing.T) {
+		e := errs.New(RegisteredReason{Name: "foo", Count: 3})
+		b, err := json.Marshal(e)
+		assert.Nil(t, err)
+		assert.Equal(t, string(b), `{"reason_type":"github.com/sttk/errs_test.RegisteredReason","reason":{"Name":"foo","Count":3},"file":"json_test.go","line":36}`)
+	})
+
+	t.Run("reason is a pointer", func(t *testing.T) {
+		e := errs.New(&RegisteredReason{Name: "foo", Count: 3})
+		b, err := json.Marshal(e)
+		assert.Nil(t, err)
+		assert.Equal(t, string(b), `{"reason_type":"*github.com/sttk/errs_test.RegisteredReason","reason":{"Name":"foo","Count":3},"file":"json_test.go","line":43}`)
+	})
+
+	t.Run("reason is a string", func(t *testing.T) {
+		e := errs.New("abc")
+		b, err := json.Marshal(e)
+		assert.Nil(t, err)
+		assert.Equal(t, string(b), `{"reason_type":"string","reason":"abc","file":"json_test.go","line":50}`)
+	})
+
+	t.Run("with cause chain", func(t *testing.T) {
+		cause := errs.New(FailToGetValue{Name: "foo"}, errors.New("def"))
+		e := errs.New(RegisteredReason{Name: "foo", Count: 3}, cause)
+		b, err := json.Marshal(e)
+		assert.Nil(t, err)
+		assert.Equal(t, string(b), `{"reason_type":"github.com/sttk/errs_test.RegisteredReason","reason":{"Name":"foo","Count":3},"file":"json_test.go","line":58,"cause":{"reason_type":"github.com/sttk/errs_test.FailToGetValue","reason":{"Name":"foo"},"file":"json_test.go","line":57,"cause":{"message":"def"}}}`)
+	})
+
+	t.Run("reason cannot be marshalled", func(t *testing.T) {
+		e := errs.New(func() {})
+		_, err := json.Marshal(e)
+		assert.NotNil(t, err)
+	})
+}
+
+func TestErr_UnmarshalJSON(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		var e errs.Err
+		assert.Nil(t, json.Unmarshal([]byte(`{}`), &e))
+		assert.True(t, e.IsOk())
+	})
+
+	t.Run("round trip of a registered reason", func(t *testing.T) {
+		cause := errs.New(&FailToGetValue{Name: "foo"}, errors.New("def"))
+		orig := errs.New(RegisteredReason{Name: "foo", Count: 3}, cause)
+		b, err := json.Marshal(orig)
+		assert.Nil(t, err)
+
+		var e errs.Err
+		assert.Nil(t, json.Unmarshal(b, &e))
+
+		assert.Equal(t, e.Reason(), RegisteredReason{Name: "foo", Count: 3})
+		assert.Equal(t, e.File(), "json_test.go")
+		assert.Equal(t, e.Line(), 80)
+		assert.Equal(t, e.Error(), orig.Error())
+
+		c, ok := e.Cause().(errs.Err)
+		assert.True(t, ok)
+		assert.Equal(t, c.Reason(), &FailToGetValue{Name: "foo"})
+		assert.Equal(t, c.Line(), 79)
+		assert.Equal(t, c.Cause().Error(), "def")
+	})
+
+	t.Run("round trip of a basic type reason", func(t *testing.T) {
+		b, err := json.Marshal(errs.New(123))
+		assert.Nil(t, err)
+
+		var e errs.Err
+		assert.Nil(t, json.Unmarshal(b, &e))
+		assert.Equal(t, e.Reason(), 123)
+	})
+
+	t.Run("unregistered reason", func(t *testing.T) {
+		b, err := json.Marshal(errs.New(&UnregisteredReason{Name: "foo"}))
+		assert.Nil(t, err)
+
+		var e errs.Err
+		assert.Nil(t, json.Unmarshal(b, &e))
+		assert.Equal(t, e.Reason(), errs.UnknownReason{
+			Type:  "*github.com/sttk/errs_test.UnregisteredReason",
+			Value: `{"Name":"foo"}`,
+		})
+		assert.Equal(t, e.Line(), 109)
+
+		b2, err := json.Marshal(e)
+		assert.Nil(t, err)
+		assert.Equal(t, string(b2), string(b))
+	})
+
+	t.Run("reason does not match the registered type", func(t *testing.T) {
+		var e errs.Err
+		err := json.Unmarshal([]byte(`{"reason_type":"github.com/sttk/errs_test.RegisteredReason","reason":{"Count":"x"}}`), &e)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		var e errs.Err
+		err := json.Unmarshal([]byte(`{"reason_type":1}`), &e)
+		assert.NotNil(t, err)
+	})
+}
+
+func TestErr_JSON_multipleCauses(t *testing.T) {
+	cause1 := errors.New("def")
+	cause2 := errs.New(RegisteredReason{Name: "foo", Count: 1})
+	err := errs.New("abc", cause1, cause2)
+
+	b, e := json.Marshal(err)
+	assert.Nil(t, e)
+	assert.Equal(t, string(b), `{"reason_type":"string","reason":"abc","file":"json_test.go","line":141,"causes":[{"message":"def"},{"reason_type":"github.com/sttk/errs_test.RegisteredReason","reason":{"Name":"foo","Count":1},"file":"json_test.go","line":140}]}`)
+
+	var err2 errs.Err
+	assert.Nil(t, json.Unmarshal(b, &err2))
+	causes := err2.Causes()
+	assert.Len(t, causes, 2)
+	assert.Equal(t, causes[0].Error(), "def")
+	c, ok := causes[1].(errs.Err)
+	assert.True(t, ok)
+	assert.Equal(t, c.Reason(), RegisteredReason{Name: "foo", Count: 1})
+	assert.Equal(t, c.Line(), 140)
+
+	b2, e := json.Marshal(err2)
+	assert.Nil(t, e)
+	assert.Equal(t, string(b2), string(b))
+}
+
+func TestBatchErr_JSON(t *testing.T) {
+	b := errs.NewBatchErr(5).
+		Add(2, errs.New(RegisteredReason{Name: "foo", Count: 1}, errors.New("def"))).
+		AddID("x", errs.New(UnregisteredReason{Name: "bar"}))
+
+	data, e := json.Marshal(b)
+	assert.Nil(t, e)
+	assert.Equal(t, string(data), `{"total":5,"failed":2,"items":[{"index":2,"err":{"reason_type":"github.com/sttk/errs_test.RegisteredReason","reason":{"Name":"foo","Count":1},"file":"json_test.go","line":164,"cause":{"message":"def"}}},{"id":"x","err":{"reason_type":"github.com/sttk/errs_test.UnregisteredReason","reason":{"Name":"bar"},"file":"json_test.go","line":165}}]}`)
+
+	b2 := errs.NewBatchErr(0)
+	assert.Nil(t, json.Unmarshal(data, b2))
+	assert.Equal(t, b2.Total(), 5)
+	assert.Equal(t, b2.Len(), 2)
+	assert.Equal(t, b2.CountsByReason(), b.CountsByReason())
+	assert.Equal(t, b2.SuccessRatio(), b.SuccessRatio())
+
+	items := b2.Items()
+	assert.Equal(t, items[0].Index, 2)
+	assert.Equal(t, items[0].ID, "")
+	assert.Equal(t, items[0].Err.Reason(), RegisteredReason{Name: "foo", Count: 1})
+	assert.Equal(t, items[0].Err.Cause().Error(), "def")
+	assert.Equal(t, items[1].Index, -1)
+	assert.Equal(t, items[1].ID, "x")
+	assert.Equal(t, items[1].Err.Reason(), errs.UnknownReason{Type: "github.com/sttk/errs_test.UnregisteredReason", Value: `{"Name":"bar"}`})
+
+	data2, e := json.Marshal(b2)
+	assert.Nil(t, e)
+	assert.Equal(t, string(data2), string(data))
+
+	t.Run("no failed item", func(t *testing.T) {
+		data, e := json.Marshal(errs.NewBatchErr(3))
+		assert.Nil(t, e)
+		assert.Equal(t, string(data), `{"total":3,"failed":0}`)
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		assert.NotNil(t, json.Unmarshal([]byte(`{"total":"x"}`), errs.NewBatchErr(0)))
+		assert.NotNil(t, json.Unmarshal([]byte(`{"items":[{"err":{"reason_type":1}}]}`), errs.NewBatchErr(0)))
+	})
+}
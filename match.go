@@ -0,0 +1,80 @@
+// Copyright (C) 2025-2026 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package errs
+
+// NoMatchingCase is the reason of an Err which Match panics with when no case matches the reason
+// of the specified Err and no default case is given.
+// The cause of the Err is the specified Err.
+type NoMatchingCase struct{}
+
+// MatchCase is a case of Match, which is created with Case or Default.
+type MatchCase[R any] struct {
+	fn        func(Err) (R, bool)
+	isDefault bool
+}
+
+// Case creates a case of Match which is selected when the reason of an Err is of the type
+// parameter T.
+// A reason of the pointer type to T, or a reason of the pointed type if T is a pointer type, also
+// matches, and it is passed to the function after being converted to T.
+func Case[T, R any](fn func(T) R) MatchCase[R] {
+	return MatchCase[R]{
+		fn: func(e Err) (R, bool) {
+			if e.IsNotOk() {
+				if r, ok := castReason[T](e.reason); ok {
+					return fn(r), true
+				}
+			}
+			var zero R
+			return zero, false
+		},
+	}
+}
+
+// Default creates a case of Match which is selected when no other case matches, including when
+// the Err indicates no error.
+func Default[R any](fn func(Err) R) MatchCase[R] {
+	return MatchCase[R]{
+		fn: func(e Err) (R, bool) {
+			return fn(e), true
+		},
+		isDefault: true,
+	}
+}
+
+// Match dispatches the specified Err to the first case whose reason type matches the reason of
+// the Err, and returns the value which the function of the case returns.
+// If no case matches, the default case created with Default is selected regardless of its
+// position. If there are multiple default cases, the first one is selected.
+//
+// If no case matches and no default case is given, this function panics with an Err whose reason
+// is NoMatchingCase and whose cause is the specified Err. Like other Err(s), it is notified to the
+// error handlers, so an unhandled reason can be reported even if the panic is recovered.
+//
+//	msg := errs.Match(err,
+//	    errs.Case(func(r NotFound) string { return "not found: " + r.Key }),
+//	    errs.Case(func(r *Timeout) string { return "timeout" }),
+//	    errs.Default(func(e errs.Err) string { return e.Error() }),
+//	)
+func Match[R any](e Err, cases ...MatchCase[R]) R {
+	var def *MatchCase[R]
+	for i := range cases {
+		c := &cases[i]
+		if c.isDefault {
+			if def == nil {
+				def = c
+			}
+			continue
+		}
+		if v, ok := c.fn(e); ok {
+			return v
+		}
+	}
+	if def != nil {
+		v, _ := def.fn(e)
+		return v
+	}
+	panic(NewSkip(1, NoMatchingCase{}, e))
+}
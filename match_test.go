@@ -0,0 +1,87 @@
+package errs_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/errs"
+)
+
+func TestMatch(t *testing.T) {
+	match := func(err errs.Err) string {
+		return errs.Match(err,
+			errs.Case(func(r InvalidValue) string { return "InvalidValue:" + r.Name }),
+			errs.Case(func(r *FailToGetValue) string { return "FailToGetValue:" + r.Name }),
+			errs.Default(func(e errs.Err) string { return fmt.Sprintf("default:%v", e.Reason()) }),
+		)
+	}
+
+	t.Run("value case", func(t *testing.T) {
+		assert.Equal(t, match(errs.New(InvalidValue{Name: "abc"})), "InvalidValue:abc")
+		assert.Equal(t, match(errs.New(&InvalidValue{Name: "abc"})), "InvalidValue:abc")
+	})
+
+	t.Run("pointer case", func(t *testing.T) {
+		assert.Equal(t, match(errs.New(FailToGetValue{Name: "abc"})), "FailToGetValue:abc")
+		assert.Equal(t, match(errs.New(&FailToGetValue{Name: "abc"})), "FailToGetValue:abc")
+	})
+
+	t.Run("default", func(t *testing.T) {
+		assert.Equal(t, match(errs.New("xyz")), "default:xyz")
+		assert.Equal(t, match(errs.Ok()), "default:<nil>")
+	})
+
+	t.Run("nil pointer reason", func(t *testing.T) {
+		var r *InvalidValue
+		v := errs.Match(errs.New(r),
+			errs.Case(func(r InvalidValue) int { return 1 }),
+			errs.Default(func(e errs.Err) int { return 2 }),
+		)
+		assert.Equal(t, v, 2)
+	})
+
+	t.Run("first matching case", func(t *testing.T) {
+		v := errs.Match(errs.New(FailToGetValue{Name: "abc"}),
+			errs.Case(func(r InvalidValue) int { return 1 }),
+			errs.Case(func(r reasonNameGetter) int { return 2 }),
+			errs.Case(func(r FailToGetValue) int { return 3 }),
+		)
+		assert.Equal(t, v, 2)
+	})
+
+	t.Run("default in any position", func(t *testing.T) {
+		v := errs.Match(errs.New(FailToGetValue{Name: "abc"}),
+			errs.Default(func(e errs.Err) int { return 1 }),
+			errs.Case(func(r FailToGetValue) int { return 2 }),
+			errs.Default(func(e errs.Err) int { return 3 }),
+		)
+		assert.Equal(t, v, 2)
+
+		v = errs.Match(errs.New(InvalidValue{Name: "abc"}),
+			errs.Default(func(e errs.Err) int { return 1 }),
+			errs.Case(func(r FailToGetValue) int { return 2 }),
+			errs.Default(func(e errs.Err) int { return 3 }),
+		)
+		assert.Equal(t, v, 1)
+	})
+
+	t.Run("no matching case", func(t *testing.T) {
+		err := errs.New(InvalidValue{Name: "abc"})
+
+		defer func() {
+			r := recover()
+			e, ok := r.(errs.Err)
+			assert.True(t, ok)
+			assert.Equal(t, e.Reason(), errs.NoMatchingCase{})
+			assert.Equal(t, e.Cause(), err)
+			assert.Equal(t, e.File(), "match_test.go")
+			assert.Equal(t, e.Line(), 82)
+		}()
+
+		errs.Match(err,
+			errs.Case(func(r FailToGetValue) int { return 1 }),
+		)
+		assert.Fail(t, "not panicked")
+	})
+}
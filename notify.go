@@ -1,5 +1,3 @@
-//go:build github.sttk.errs.notify
-
 // Copyright (C) 2025-2026 Takayuki Sato. All Rights Reserved.
 // This program is free software under MIT License.
 // See the file LICENSE in this distribution for more details.
@@ -7,72 +5,337 @@
 package errs
 
 import (
+	"context"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
+// errHandlerRegistry is an immutable snapshot of the error handlers and the state of the
+// notification. A snapshot is never modified after it is stored, and every update stores a
+// modified copy while holding errHandlersMutex, so that notifyErr can read the handlers with
+// only an atomic load and without a lock.
+type errHandlerRegistry struct {
+	syncHandlers        []func(Err, time.Time)
+	asyncHandlers       []func(Err, time.Time)
+	syncReasonHandlers  map[reflect.Type][]func(Err, time.Time)
+	asyncReasonHandlers map[reflect.Type][]func(Err, time.Time)
+	pool                *errHandlerPool
+	enabled             bool
+	fixed               bool
+	shut                bool
+}
+
 var (
-	syncErrHandlers    []func(Err, time.Time)
-	asyncErrHandlers   []func(Err, time.Time)
-	isErrHandlersFixed = false
+	errHandlers      atomic.Value // *errHandlerRegistry
+	errHandlersMutex sync.Mutex
 )
 
+// ErrNotifyEnv is the name of the environment variable which enables or disables the notification
+// of Err instantiations when the program starts.
+// The notification is enabled if its value is "1", "true", or "on", and disabled if its value is
+// "0", "false", or "off", regardless of case. Otherwise, the default is used, which is enabled
+// only when the program is built with the build tag: github.sttk.errs.notify
+const ErrNotifyEnv = "GITHUB_STTK_ERRS_NOTIFY"
+
+func init() {
+	enabled := isErrNotificationEnabledBy(os.Getenv(ErrNotifyEnv), errNotificationEnabledByDefault)
+	errHandlers.Store(&errHandlerRegistry{enabled: enabled})
+}
+
+func isErrNotificationEnabledBy(env string, def bool) bool {
+	switch strings.ToLower(strings.TrimSpace(env)) {
+	case "1", "true", "on":
+		return true
+	case "0", "false", "off":
+		return false
+	default:
+		return def
+	}
+}
+
+func loadErrHandlers() *errHandlerRegistry {
+	return errHandlers.Load().(*errHandlerRegistry)
+}
+
+// updateErrHandlers applies the function to a copy of the current snapshot and stores the copy.
+// It does nothing if the handlers have been fixed.
+func updateErrHandlers(fn func(r *errHandlerRegistry)) {
+	errHandlersMutex.Lock()
+	defer errHandlersMutex.Unlock()
+
+	cur := loadErrHandlers()
+	if cur.fixed {
+		return
+	}
+	next := cur.clone()
+	fn(next)
+	errHandlers.Store(next)
+}
+
+func (r *errHandlerRegistry) clone() *errHandlerRegistry {
+	c := *r
+	c.syncHandlers = copyHandlers(r.syncHandlers)
+	c.asyncHandlers = copyHandlers(r.asyncHandlers)
+	c.syncReasonHandlers = copyReasonHandlers(r.syncReasonHandlers)
+	c.asyncReasonHandlers = copyReasonHandlers(r.asyncReasonHandlers)
+	return &c
+}
+
+func copyHandlers(s []func(Err, time.Time)) []func(Err, time.Time) {
+	if len(s) == 0 {
+		return nil
+	}
+	c := make([]func(Err, time.Time), len(s))
+	copy(c, s)
+	return c
+}
+
+func copyReasonHandlers(
+	m map[reflect.Type][]func(Err, time.Time),
+) map[reflect.Type][]func(Err, time.Time) {
+	c := make(map[reflect.Type][]func(Err, time.Time), len(m))
+	for t, handlers := range m {
+		c[t] = copyHandlers(handlers)
+	}
+	return c
+}
+
+// isErrHandlersFixed reports whether the handlers have been fixed.
+// This must be called while holding errHandlersMutex to keep the result valid until the
+// configuration is updated.
+func isErrHandlersFixed() bool {
+	return loadErrHandlers().fixed
+}
+
+// EnableErrNotification enables the notification of Err instantiations to the error handlers.
+// Unlike the registration of handlers, the notification can be enabled or disabled at any time,
+// even after the handlers have been fixed. However, no Err is notified until FixErrHandlers is
+// called, or after ShutdownErrHandlers is called.
+func EnableErrNotification() {
+	setErrNotificationEnabled(true)
+}
+
+// DisableErrNotification disables the notification of Err instantiations to the error handlers.
+// While the notification is disabled, creating an Err costs only a single atomic load for the
+// notification.
+func DisableErrNotification() {
+	setErrNotificationEnabled(false)
+}
+
+// IsErrNotificationEnabled reports whether the notification of Err instantiations is enabled.
+func IsErrNotificationEnabled() bool {
+	return loadErrHandlers().enabled
+}
+
+func setErrNotificationEnabled(enabled bool) {
+	errHandlersMutex.Lock()
+	defer errHandlersMutex.Unlock()
+
+	cur := loadErrHandlers()
+	if cur.enabled == enabled {
+		return
+	}
+	next := *cur
+	next.enabled = enabled
+	errHandlers.Store(&next)
+}
+
 // AddSyncErrHandler adds a new synchronous error handler to the global handler list.
 // It will not add the handler if the handlers have been fixed using FixErrHandlers.
-//
-// NOTE: This function is enabled via the build tag: github.sttk.errs.notify
 func AddSyncErrHandler(handler func(Err, time.Time)) {
-	if isErrHandlersFixed {
-		return
-	}
-	syncErrHandlers = append(syncErrHandlers, handler)
+	updateErrHandlers(func(r *errHandlerRegistry) {
+		r.syncHandlers = append(r.syncHandlers, handler)
+	})
 }
 
 // AddAsyncErrHandler adds a new asynchronous error handler to the global handler list.
 // It will not add the handler if the handlers have been fixed using FixErrHandlers.
-//
-// NOTE: This function is enabled via the build tag: github.sttk.errs.notify
 func AddAsyncErrHandler(handler func(Err, time.Time)) {
-	if isErrHandlersFixed {
-		return
+	updateErrHandlers(func(r *errHandlerRegistry) {
+		r.asyncHandlers = append(r.asyncHandlers, handler)
+	})
+}
+
+// AddSyncReasonHandler adds a new synchronous error handler which is called only for Err(s) whose
+// reason is of the type parameter T.
+// A reason of the pointer type to T, or a reason of the pointed type if T is a pointer type, also
+// matches, and it is passed to the handler after being converted to T.
+// It will not add the handler if the handlers have been fixed using FixErrHandlers.
+//
+// Handlers for a concrete type T are looked up by the type of the reason, so they are not called
+// for Err(s) with other reasons at all. Handlers for an interface type T are checked for every
+// Err like the handlers added with AddSyncErrHandler.
+func AddSyncReasonHandler[T any](handler func(T, Err, time.Time)) {
+	t, fn := reasonHandler(handler)
+	updateErrHandlers(func(r *errHandlerRegistry) {
+		if t.Kind() == reflect.Interface {
+			r.syncHandlers = append(r.syncHandlers, fn)
+			return
+		}
+		r.syncReasonHandlers[t] = append(r.syncReasonHandlers[t], fn)
+	})
+}
+
+// AddAsyncReasonHandler adds a new asynchronous error handler which is called only for Err(s)
+// whose reason is of the type parameter T.
+// A reason of the pointer type to T, or a reason of the pointed type if T is a pointer type, also
+// matches, and it is passed to the handler after being converted to T.
+// It will not add the handler if the handlers have been fixed using FixErrHandlers.
+//
+// Handlers for a concrete type T are looked up by the type of the reason, so they are not called
+// for Err(s) with other reasons at all. Handlers for an interface type T are checked for every
+// Err like the handlers added with AddAsyncErrHandler.
+func AddAsyncReasonHandler[T any](handler func(T, Err, time.Time)) {
+	t, fn := reasonHandler(handler)
+	updateErrHandlers(func(r *errHandlerRegistry) {
+		if t.Kind() == reflect.Interface {
+			r.asyncHandlers = append(r.asyncHandlers, fn)
+			return
+		}
+		r.asyncReasonHandlers[t] = append(r.asyncReasonHandlers[t], fn)
+	})
+}
+
+func reasonHandler[T any](handler func(T, Err, time.Time)) (reflect.Type, func(Err, time.Time)) {
+	t := reasonKey(reflect.TypeOf((*T)(nil)).Elem())
+	fn := func(e Err, tm time.Time) {
+		if r, ok := castReason[T](e.reason); ok {
+			handler(r, e, tm)
+		}
 	}
-	asyncErrHandlers = append(asyncErrHandlers, handler)
+	return t, fn
+}
+
+func reasonKey(t reflect.Type) reflect.Type {
+	if t != nil && t.Kind() == reflect.Ptr {
+		return t.Elem()
+	}
+	return t
 }
 
 // FixErrHandlers prevents further modification of the error handler lists.
 // Before this is called, no Err is notified to the handlers.
 // After this is called, no new handlers can be added, and Err(s) is notified to the
 // handlers.
-//
-// NOTE: This function is enabled via the build tag: github.sttk.errs.notify
+// The worker goroutines which run asynchronous handlers are started at this time, and every
+// handler is wrapped so that a panic in it is recovered. (See SetErrHandlerPanicHook.)
 func FixErrHandlers() {
-	if isErrHandlersFixed {
-		return
+	updateErrHandlers(func(r *errHandlerRegistry) {
+		r.fixed = true
+
+		guardErrHandlers(r.syncHandlers)
+		guardErrHandlers(r.asyncHandlers)
+		for _, handlers := range r.syncReasonHandlers {
+			guardErrHandlers(handlers)
+		}
+		for _, handlers := range r.asyncReasonHandlers {
+			guardErrHandlers(handlers)
+		}
+
+		numAsyncHandlers := len(r.asyncHandlers)
+		for _, handlers := range r.asyncReasonHandlers {
+			numAsyncHandlers += len(handlers)
+		}
+		if numAsyncHandlers == 0 {
+			return
+		}
+
+		r.pool = newErrHandlerPool(numAsyncHandlers)
+		for i, handler := range r.asyncHandlers {
+			r.asyncHandlers[i] = r.pool.bind(handler)
+		}
+		for _, handlers := range r.asyncReasonHandlers {
+			for i, handler := range handlers {
+				handlers[i] = r.pool.bind(handler)
+			}
+		}
+	})
+}
+
+// FlushErrHandlers waits until all pending invocations of asynchronous error handlers have
+// finished, or the specified context is done.
+// If the context is done before that, this function returns the context's error.
+//
+// While this function is waiting, Err(s) created concurrently are still notified, and their
+// invocations are waited for as well.
+// After ShutdownErrHandlers is called, this function returns nil immediately.
+func FlushErrHandlers(ctx context.Context) error {
+	r := loadErrHandlers()
+	if r.pool == nil || r.shut {
+		return nil
 	}
-	isErrHandlersFixed = true
-	syncErrHandlers = clip(syncErrHandlers)
-	asyncErrHandlers = clip(asyncErrHandlers)
+	return r.pool.flush(ctx)
 }
 
-func clip(s []func(Err, time.Time)) []func(Err, time.Time) {
-	return s[:len(s):len(s)]
+// ShutdownErrHandlers stops notifying Err(s) to the error handlers, and waits until all pending
+// invocations of asynchronous error handlers have finished, or the specified context is done.
+// After that, the worker goroutines which run asynchronous handlers are stopped, and pending
+// invocations that have not started yet are abandoned.
+// If the context is done before all pending invocations have finished, this function returns
+// the context's error.
+//
+// This function also fixes the error handlers if they have not been fixed yet, and once this
+// function is called, no Err is notified any more.
+// This function is intended to be called when the process is exiting, so as not to lose the last
+// Err(s) before the exit.
+func ShutdownErrHandlers(ctx context.Context) error {
+	errHandlersMutex.Lock()
+	cur := loadErrHandlers()
+	if cur.shut {
+		errHandlersMutex.Unlock()
+		return nil
+	}
+	next := *cur
+	next.fixed = true
+	next.shut = true
+	errHandlers.Store(&next)
+	errHandlersMutex.Unlock()
+
+	if next.pool == nil {
+		return nil
+	}
+	err := next.pool.flush(ctx)
+	next.pool.stop()
+	return err
 }
 
 func notifyErr(e Err) {
-	if !isErrHandlersFixed {
+	r := loadErrHandlers()
+	if !r.enabled || !r.fixed || r.shut {
 		return
 	}
 
-	if len(syncErrHandlers) == 0 && len(asyncErrHandlers) == 0 {
+	var syncHandlersForReason, asyncHandlersForReason []func(Err, time.Time)
+	if e.reason != nil {
+		key := reasonKey(reflect.TypeOf(e.reason))
+		syncHandlersForReason = r.syncReasonHandlers[key]
+		asyncHandlersForReason = r.asyncReasonHandlers[key]
+	}
+
+	if len(r.syncHandlers) == 0 && len(r.asyncHandlers) == 0 &&
+		len(syncHandlersForReason) == 0 && len(asyncHandlersForReason) == 0 {
 		return
 	}
 
 	tm := time.Now().UTC()
 
-	for _, handler := range syncErrHandlers {
+	for _, handler := range r.syncHandlers {
+		handler(e, tm)
+	}
+	for _, handler := range syncHandlersForReason {
 		handler(e, tm)
 	}
 
-	for _, handler := range asyncErrHandlers {
-		go handler(e, tm)
+	// Asynchronous handlers have been bound to the workers of the pool by FixErrHandlers, so
+	// these calls only enqueue the invocations.
+	for _, handler := range r.asyncHandlers {
+		handler(e, tm)
+	}
+	for _, handler := range asyncHandlersForReason {
+		handler(e, tm)
 	}
 }
@@ -6,4 +6,4 @@
 
 package errs
 
-func notifyErr(e Err) {}
+const errNotificationEnabledByDefault = false
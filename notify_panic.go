@@ -0,0 +1,128 @@
+// Copyright (C) 2025-2026 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package errs
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"runtime/debug"
+	"sync/atomic"
+	"time"
+)
+
+// ErrHandlerPanic is the struct which represents a panic raised in an error handler.
+// This is passed to the hook set with SetErrHandlerPanicHook.
+type ErrHandlerPanic struct {
+	// Value is the value passed to panic.
+	Value any
+
+	// Stack is the stack trace of the goroutine where the panic was raised.
+	Stack []byte
+
+	// Err is the Err which was being notified to the handler.
+	Err Err
+
+	// Time is the time which was being notified to the handler.
+	Time time.Time
+
+	// Disabled indicates whether the handler was disabled by this panic because it panicked
+	// consecutively as many times as the limit set with SetErrHandlerPanicLimit.
+	Disabled bool
+}
+
+var (
+	errHandlerPanicHook  func(ErrHandlerPanic) = reportErrHandlerPanic
+	errHandlerPanicLimit                       = 0
+	errHandlerPanicOut   io.Writer             = os.Stderr
+)
+
+// SetErrHandlerPanicHook sets the function which is called when an error handler panics.
+// A panic in an error handler, whether synchronous or asynchronous, is recovered and converted
+// to an ErrHandlerPanic, which is passed to this hook, so that it does not propagate out of New
+// or terminate the process.
+// A panic raised in the hook itself is ignored.
+//
+// By default, the panic is reported to stderr. If nil is specified, the default is restored.
+// This hook will not be changed if the handlers have been fixed using FixErrHandlers.
+func SetErrHandlerPanicHook(hook func(ErrHandlerPanic)) {
+	errHandlersMutex.Lock()
+	defer errHandlersMutex.Unlock()
+
+	if isErrHandlersFixed() {
+		return
+	}
+	if hook == nil {
+		hook = reportErrHandlerPanic
+	}
+	errHandlerPanicHook = hook
+}
+
+// SetErrHandlerPanicLimit sets the number of consecutive panics after which an error handler is
+// disabled. A disabled handler is no longer called. The count of consecutive panics is reset
+// when the handler returns normally.
+//
+// If the limit is zero or negative, handlers are never disabled. This is the default.
+// This limit will not be changed if the handlers have been fixed using FixErrHandlers.
+func SetErrHandlerPanicLimit(limit int) {
+	errHandlersMutex.Lock()
+	defer errHandlersMutex.Unlock()
+
+	if isErrHandlersFixed() {
+		return
+	}
+	if limit < 0 {
+		limit = 0
+	}
+	errHandlerPanicLimit = limit
+}
+
+func reportErrHandlerPanic(p ErrHandlerPanic) {
+	msg := "errs: error handler panicked: %v\n%s"
+	if p.Disabled {
+		msg = "errs: error handler panicked and was disabled: %v\n%s"
+	}
+	fmt.Fprintf(errHandlerPanicOut, msg, p.Value, p.Stack)
+}
+
+// guardErrHandler wraps the handler so that a panic in it is recovered and passed to the hook.
+func guardErrHandler(handler func(Err, time.Time)) func(Err, time.Time) {
+	hook := errHandlerPanicHook
+	limit := int32(errHandlerPanicLimit)
+
+	var consecutivePanics int32
+	var disabled int32
+
+	return func(e Err, tm time.Time) {
+		if atomic.LoadInt32(&disabled) != 0 {
+			return
+		}
+
+		defer func() {
+			v := recover()
+			if v == nil {
+				atomic.StoreInt32(&consecutivePanics, 0)
+				return
+			}
+
+			p := ErrHandlerPanic{Value: v, Stack: debug.Stack(), Err: e, Time: tm}
+			n := atomic.AddInt32(&consecutivePanics, 1)
+			if limit > 0 && n >= limit && atomic.CompareAndSwapInt32(&disabled, 0, 1) {
+				p.Disabled = true
+			}
+
+			defer func() { _ = recover() }()
+			hook(p)
+		}()
+
+		handler(e, tm)
+	}
+}
+
+func guardErrHandlers(handlers []func(Err, time.Time)) {
+	for i, handler := range handlers {
+		handlers[i] = guardErrHandler(handler)
+	}
+}
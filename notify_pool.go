@@ -0,0 +1,288 @@
+// Copyright (C) 2025-2026 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package errs
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// OverflowPolicy is the type of the policies which determine what to do when the queue of a
+// worker running asynchronous error handlers is full.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock makes the notification wait until the queue has space.
+	// In this case, the creation of an Err is blocked while the queue is full.
+	// However, a notification of an Err created in an asynchronous handler is discarded instead,
+	// because the worker running the handler could wait for its own queue forever.
+	OverflowBlock OverflowPolicy = iota
+
+	// OverflowDropNewest discards the new notification when the queue is full.
+	OverflowDropNewest
+
+	// OverflowDropOldest discards the oldest notification in the queue to make space for the new
+	// notification when the queue is full.
+	OverflowDropOldest
+)
+
+const defaultAsyncErrHandlerQueueSize = 1024
+
+var (
+	asyncErrHandlerWorkers   = 0
+	asyncErrHandlerQueueSize = defaultAsyncErrHandlerQueueSize
+	asyncErrHandlerOverflow  = OverflowBlock
+	droppedErrNotifications  uint64
+)
+
+// SetAsyncErrHandlerPool configures the pool of worker goroutines which run asynchronous error
+// handlers.
+//
+// The argument workers is the maximum number of worker goroutines. If it is zero or negative,
+// one worker is started for each asynchronous handler. The argument queueSize is the capacity of
+// the queue of each worker, and the argument policy determines what to do when a queue is full.
+// Each asynchronous handler is assigned to a single worker, so the invocations of a handler are
+// performed in the order of the notifications.
+//
+// An asynchronous handler can create Err(s), directly or through other libraries, but the
+// notifications of them never block the worker running the handler: when the queue is full, they
+// are discarded even with OverflowBlock.
+//
+// The default configuration is: one worker per handler, a queue size of 1024, and OverflowBlock.
+// This configuration will not be changed if the handlers have been fixed using FixErrHandlers,
+// because the workers are started at that time.
+func SetAsyncErrHandlerPool(workers, queueSize int, policy OverflowPolicy) {
+	errHandlersMutex.Lock()
+	defer errHandlersMutex.Unlock()
+
+	if isErrHandlersFixed() {
+		return
+	}
+	if workers < 0 {
+		workers = 0
+	}
+	if queueSize < 1 {
+		queueSize = 1
+	}
+	asyncErrHandlerWorkers = workers
+	asyncErrHandlerQueueSize = queueSize
+	asyncErrHandlerOverflow = policy
+}
+
+// DroppedErrNotifications returns the number of asynchronous error notifications which were
+// discarded according to the overflow policy because the queue of a worker was full.
+func DroppedErrNotifications() uint64 {
+	return atomic.LoadUint64(&droppedErrNotifications)
+}
+
+type errHandlerJob struct {
+	handler func(Err, time.Time)
+	err     Err
+	tm      time.Time
+}
+
+type errHandlerPool struct {
+	queues   []chan errHandlerJob
+	overflow OverflowPolicy
+	assigned int
+	done     chan struct{}
+	stopOnce sync.Once
+	pending  int64
+	mutex    sync.Mutex
+	waiters  []chan struct{}
+}
+
+func newErrHandlerPool(numHandlers int) *errHandlerPool {
+	n := asyncErrHandlerWorkers
+	if n <= 0 || n > numHandlers {
+		n = numHandlers
+	}
+	pool := &errHandlerPool{
+		queues:   make([]chan errHandlerJob, n),
+		overflow: asyncErrHandlerOverflow,
+		done:     make(chan struct{}),
+	}
+	for i := range pool.queues {
+		q := make(chan errHandlerJob, asyncErrHandlerQueueSize)
+		pool.queues[i] = q
+		go pool.runWorker(q)
+	}
+	return pool
+}
+
+func (pool *errHandlerPool) runWorker(q <-chan errHandlerJob) {
+	for {
+		select {
+		case <-pool.done:
+			return
+		default:
+		}
+		select {
+		case job := <-q:
+			job.handler(job.err, job.tm)
+			pool.finish()
+		case <-pool.done:
+			return
+		}
+	}
+}
+
+// bind assigns the handler to a worker and returns a function which enqueues an invocation of
+// the handler to the queue of that worker.
+func (pool *errHandlerPool) bind(handler func(Err, time.Time)) func(Err, time.Time) {
+	q := pool.queues[pool.assigned%len(pool.queues)]
+	pool.assigned++
+	return func(e Err, tm time.Time) {
+		pool.enqueue(q, errHandlerJob{handler: handler, err: e, tm: tm})
+	}
+}
+
+func (pool *errHandlerPool) enqueue(q chan errHandlerJob, job errHandlerJob) {
+	atomic.AddInt64(&pool.pending, 1)
+
+	select {
+	case <-pool.done:
+		pool.finish()
+		return
+	default:
+	}
+
+	// A notification which loaded the snapshot before the shutdown can enqueue a job after the
+	// queue has been drained by stop, so the queue is drained again in that case.
+	defer func() {
+		select {
+		case <-pool.done:
+			pool.drain(q)
+		default:
+		}
+	}()
+
+	switch pool.overflow {
+	case OverflowDropNewest:
+		select {
+		case q <- job:
+		default:
+			atomic.AddUint64(&droppedErrNotifications, 1)
+			pool.finish()
+		}
+	case OverflowDropOldest:
+		for {
+			select {
+			case q <- job:
+				return
+			default:
+			}
+			select {
+			case <-q:
+				atomic.AddUint64(&droppedErrNotifications, 1)
+				pool.finish()
+			default:
+			}
+		}
+	default:
+		select {
+		case q <- job:
+			return
+		default:
+		}
+		if isOnErrHandlerWorker() {
+			atomic.AddUint64(&droppedErrNotifications, 1)
+			pool.finish()
+			return
+		}
+		select {
+		case q <- job:
+		case <-pool.done:
+			pool.finish()
+		}
+	}
+}
+
+// isOnErrHandlerWorker reports whether the current goroutine is a worker running asynchronous
+// error handlers. This is checked only when a queue is full, because it walks the whole stack.
+func isOnErrHandlerWorker() bool {
+	pcs := make([]uintptr, 64)
+	for {
+		n := runtime.Callers(2, pcs)
+		if n < len(pcs) {
+			pcs = pcs[:n]
+			break
+		}
+		pcs = make([]uintptr, len(pcs)*2)
+	}
+	frames := runtime.CallersFrames(pcs)
+	for {
+		f, more := frames.Next()
+		if strings.HasSuffix(f.Function, ".(*errHandlerPool).runWorker") {
+			return true
+		}
+		if !more {
+			return false
+		}
+	}
+}
+
+// finish decrements the number of pending invocations, and wakes up the waiters of flush when
+// there is no pending invocation.
+func (pool *errHandlerPool) finish() {
+	if atomic.AddInt64(&pool.pending, -1) != 0 {
+		return
+	}
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+	if atomic.LoadInt64(&pool.pending) != 0 {
+		return
+	}
+	for _, w := range pool.waiters {
+		close(w)
+	}
+	pool.waiters = nil
+}
+
+// flush waits until there is no pending invocation, or the context is done.
+func (pool *errHandlerPool) flush(ctx context.Context) error {
+	pool.mutex.Lock()
+	if atomic.LoadInt64(&pool.pending) == 0 {
+		pool.mutex.Unlock()
+		return nil
+	}
+	w := make(chan struct{})
+	pool.waiters = append(pool.waiters, w)
+	pool.mutex.Unlock()
+
+	select {
+	case <-w:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// stop stops the workers, and abandons the invocations remaining in the queues, which are no
+// longer pending.
+func (pool *errHandlerPool) stop() {
+	pool.stopOnce.Do(func() {
+		close(pool.done)
+	})
+	for _, q := range pool.queues {
+		pool.drain(q)
+	}
+}
+
+// drain removes all the jobs in the queue, and finishes them without invoking their handlers.
+func (pool *errHandlerPool) drain(q chan errHandlerJob) {
+	for {
+		select {
+		case <-q:
+			pool.finish()
+		default:
+			return
+		}
+	}
+}
@@ -0,0 +1,9 @@
+//go:build github.sttk.errs.notify
+
+// Copyright (C) 2025-2026 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package errs
+
+const errNotificationEnabledByDefault = true
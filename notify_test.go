@@ -1,11 +1,15 @@
-//go:build github.sttk.errs.notify
-
 package errs
 
 import (
+	"bytes"
 	"container/list"
+	"context"
 	"fmt"
+	"os"
 	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -13,9 +17,16 @@ import (
 )
 
 func ClearErrHandlers() {
-	syncErrHandlers = nil
-	asyncErrHandlers = nil
-	isErrHandlersFixed = false
+	if pool := loadErrHandlers().pool; pool != nil {
+		pool.stop()
+	}
+	errHandlers.Store(&errHandlerRegistry{enabled: true})
+	asyncErrHandlerWorkers = 0
+	asyncErrHandlerQueueSize = defaultAsyncErrHandlerQueueSize
+	asyncErrHandlerOverflow = OverflowBlock
+	droppedErrNotifications = 0
+	errHandlerPanicHook = reportErrHandlerPanic
+	errHandlerPanicLimit = 0
 }
 
 func TestAddErrSyncHandler(t *testing.T) {
@@ -25,8 +36,8 @@ func TestAddErrSyncHandler(t *testing.T) {
 		ClearErrHandlers()
 		defer ClearErrHandlers()
 
-		assert.Empty(t, syncErrHandlers)
-		assert.Empty(t, syncErrHandlers)
+		assert.Empty(t, loadErrHandlers().syncHandlers)
+		assert.Empty(t, loadErrHandlers().syncHandlers)
 	})
 
 	t.Run("add one handler", func(t *testing.T) {
@@ -35,10 +46,10 @@ func TestAddErrSyncHandler(t *testing.T) {
 
 		AddSyncErrHandler(func(e Err, tm time.Time) {})
 
-		assert.Len(t, syncErrHandlers, 1)
-		assert.Equal(t, reflect.TypeOf(syncErrHandlers[0]).String(), fn_sig)
+		assert.Len(t, loadErrHandlers().syncHandlers, 1)
+		assert.Equal(t, reflect.TypeOf(loadErrHandlers().syncHandlers[0]).String(), fn_sig)
 
-		assert.Empty(t, asyncErrHandlers)
+		assert.Empty(t, loadErrHandlers().asyncHandlers)
 	})
 
 	t.Run("add two handler", func(t *testing.T) {
@@ -48,11 +59,11 @@ func TestAddErrSyncHandler(t *testing.T) {
 		AddSyncErrHandler(func(e Err, tm time.Time) {})
 		AddSyncErrHandler(func(e Err, tm time.Time) {})
 
-		assert.Len(t, syncErrHandlers, 2)
-		assert.Equal(t, reflect.TypeOf(syncErrHandlers[0]).String(), fn_sig)
-		assert.Equal(t, reflect.TypeOf(syncErrHandlers[1]).String(), fn_sig)
+		assert.Len(t, loadErrHandlers().syncHandlers, 2)
+		assert.Equal(t, reflect.TypeOf(loadErrHandlers().syncHandlers[0]).String(), fn_sig)
+		assert.Equal(t, reflect.TypeOf(loadErrHandlers().syncHandlers[1]).String(), fn_sig)
 
-		assert.Empty(t, asyncErrHandlers)
+		assert.Empty(t, loadErrHandlers().asyncHandlers)
 	})
 }
 
@@ -63,7 +74,7 @@ func TestAddErrAsyncHandler(t *testing.T) {
 		ClearErrHandlers()
 		defer ClearErrHandlers()
 
-		assert.Empty(t, asyncErrHandlers)
+		assert.Empty(t, loadErrHandlers().asyncHandlers)
 	})
 
 	t.Run("add one handler", func(t *testing.T) {
@@ -72,10 +83,10 @@ func TestAddErrAsyncHandler(t *testing.T) {
 
 		AddAsyncErrHandler(func(e Err, tm time.Time) {})
 
-		assert.Empty(t, syncErrHandlers)
+		assert.Empty(t, loadErrHandlers().syncHandlers)
 
-		assert.Len(t, asyncErrHandlers, 1)
-		assert.Equal(t, reflect.TypeOf(asyncErrHandlers[0]).String(), fn_sig)
+		assert.Len(t, loadErrHandlers().asyncHandlers, 1)
+		assert.Equal(t, reflect.TypeOf(loadErrHandlers().asyncHandlers[0]).String(), fn_sig)
 	})
 
 	t.Run("add two handler", func(t *testing.T) {
@@ -85,11 +96,11 @@ func TestAddErrAsyncHandler(t *testing.T) {
 		AddAsyncErrHandler(func(e Err, tm time.Time) {})
 		AddAsyncErrHandler(func(e Err, tm time.Time) {})
 
-		assert.Empty(t, syncErrHandlers)
+		assert.Empty(t, loadErrHandlers().syncHandlers)
 
-		assert.Len(t, asyncErrHandlers, 2)
-		assert.Equal(t, reflect.TypeOf(asyncErrHandlers[0]).String(), fn_sig)
-		assert.Equal(t, reflect.TypeOf(asyncErrHandlers[1]).String(), fn_sig)
+		assert.Len(t, loadErrHandlers().asyncHandlers, 2)
+		assert.Equal(t, reflect.TypeOf(loadErrHandlers().asyncHandlers[0]).String(), fn_sig)
+		assert.Equal(t, reflect.TypeOf(loadErrHandlers().asyncHandlers[1]).String(), fn_sig)
 	})
 }
 
@@ -101,20 +112,20 @@ func TestFixErrHandlers(t *testing.T) {
 		AddSyncErrHandler(func(e Err, tm time.Time) {})
 		AddAsyncErrHandler(func(e Err, tm time.Time) {})
 
-		assert.Len(t, syncErrHandlers, 1)
-		assert.Len(t, asyncErrHandlers, 1)
+		assert.Len(t, loadErrHandlers().syncHandlers, 1)
+		assert.Len(t, loadErrHandlers().asyncHandlers, 1)
 
-		assert.False(t, isErrHandlersFixed)
+		assert.False(t, isErrHandlersFixed())
 
 		FixErrHandlers()
 
-		assert.True(t, isErrHandlersFixed)
+		assert.True(t, isErrHandlersFixed())
 
 		AddSyncErrHandler(func(e Err, tm time.Time) {})
 		AddAsyncErrHandler(func(e Err, tm time.Time) {})
 
-		assert.Len(t, syncErrHandlers, 1)
-		assert.Len(t, asyncErrHandlers, 1)
+		assert.Len(t, loadErrHandlers().syncHandlers, 1)
+		assert.Len(t, loadErrHandlers().asyncHandlers, 1)
 	})
 }
 
@@ -125,11 +136,11 @@ func TestNotifyErr(t *testing.T) {
 
 		type FailToDoSomething struct{}
 
-		assert.False(t, isErrHandlersFixed)
+		assert.False(t, isErrHandlersFixed())
 		New(FailToDoSomething{})
 
 		FixErrHandlers()
-		assert.True(t, isErrHandlersFixed)
+		assert.True(t, isErrHandlersFixed())
 		New(FailToDoSomething{})
 	})
 
@@ -139,6 +150,7 @@ func TestNotifyErr(t *testing.T) {
 
 		syncLogs := list.New()
 		asyncLogs := list.New()
+		var asyncMutex sync.Mutex
 
 		type FailToDoSomething struct{}
 
@@ -150,14 +162,18 @@ func TestNotifyErr(t *testing.T) {
 		})
 		AddAsyncErrHandler(func(e Err, tm time.Time) {
 			time.Sleep(100 * time.Millisecond)
+			asyncMutex.Lock()
+			defer asyncMutex.Unlock()
 			asyncLogs.PushBack(fmt.Sprintf("%s-3:%s", e.Error(), tm.String()))
 		})
 		AddAsyncErrHandler(func(e Err, tm time.Time) {
 			time.Sleep(10 * time.Millisecond)
+			asyncMutex.Lock()
+			defer asyncMutex.Unlock()
 			asyncLogs.PushBack(fmt.Sprintf("%s-4:%s", e.Error(), tm.String()))
 		})
 
-		assert.False(t, isErrHandlersFixed)
+		assert.False(t, isErrHandlersFixed())
 
 		New(FailToDoSomething{})
 
@@ -166,26 +182,858 @@ func TestNotifyErr(t *testing.T) {
 
 		FixErrHandlers()
 
-		assert.True(t, isErrHandlersFixed)
+		assert.True(t, isErrHandlersFixed())
 
 		New(FailToDoSomething{})
 
 		assert.Equal(t, syncLogs.Len(), 2)
 		log := syncLogs.Front()
-		assert.Contains(t, log.Value, "github.com/sttk/errs.Err {reason:github.com/sttk/errs.FailToDoSomething file:notify_test.go line:171}-1:")
+		assert.Contains(t, log.Value, "github.com/sttk/errs.Err {reason:github.com/sttk/errs.FailToDoSomething file:notify_test.go line:187}-1:")
 		log = log.Next()
-		assert.Contains(t, log.Value, "github.com/sttk/errs.Err {reason:github.com/sttk/errs.FailToDoSomething file:notify_test.go line:171}-2:")
+		assert.Contains(t, log.Value, "github.com/sttk/errs.Err {reason:github.com/sttk/errs.FailToDoSomething file:notify_test.go line:187}-2:")
 		log = log.Next()
 		assert.Nil(t, log)
 
 		time.Sleep(500 * time.Millisecond)
 
+		asyncMutex.Lock()
+		defer asyncMutex.Unlock()
+
 		assert.Equal(t, asyncLogs.Len(), 2)
 		log = asyncLogs.Front()
-		assert.Contains(t, log.Value, "github.com/sttk/errs.Err {reason:github.com/sttk/errs.FailToDoSomething file:notify_test.go line:171}-4:")
+		assert.Contains(t, log.Value, "github.com/sttk/errs.Err {reason:github.com/sttk/errs.FailToDoSomething file:notify_test.go line:187}-4:")
 		log = log.Next()
-		assert.Contains(t, log.Value, "github.com/sttk/errs.Err {reason:github.com/sttk/errs.FailToDoSomething file:notify_test.go line:171}-3:")
+		assert.Contains(t, log.Value, "github.com/sttk/errs.Err {reason:github.com/sttk/errs.FailToDoSomething file:notify_test.go line:187}-3:")
 		log = log.Next()
 		assert.Nil(t, log)
 	})
+
+	t.Run("not notify a target", func(t *testing.T) {
+		ClearErrHandlers()
+		defer ClearErrHandlers()
+
+		type FailToDoSomething struct{}
+
+		var count int
+		AddSyncErrHandler(func(e Err, tm time.Time) {
+			count++
+		})
+		FixErrHandlers()
+
+		target := Target(FailToDoSomething{})
+		assert.Equal(t, count, 0)
+
+		assert.True(t, New(FailToDoSomething{}).Is(target))
+		assert.Equal(t, count, 1)
+	})
+}
+
+type ReasonForHandlerTest struct {
+	Name string
+}
+
+type OtherReasonForHandlerTest struct{}
+
+type reasonForHandlerTester interface {
+	TestName() string
+}
+
+func (r ReasonForHandlerTest) TestName() string {
+	return r.Name
+}
+
+func TestAddSyncReasonHandler(t *testing.T) {
+	t.Run("add handlers for a value type and a pointer type", func(t *testing.T) {
+		ClearErrHandlers()
+		defer ClearErrHandlers()
+
+		AddSyncReasonHandler(func(r ReasonForHandlerTest, e Err, tm time.Time) {})
+		AddSyncReasonHandler(func(r *ReasonForHandlerTest, e Err, tm time.Time) {})
+
+		assert.Empty(t, loadErrHandlers().syncHandlers)
+		assert.Len(t, loadErrHandlers().syncReasonHandlers, 1)
+		assert.Len(t, loadErrHandlers().syncReasonHandlers[reflect.TypeOf(ReasonForHandlerTest{})], 2)
+		assert.Empty(t, loadErrHandlers().asyncReasonHandlers)
+	})
+
+	t.Run("add a handler for an interface type", func(t *testing.T) {
+		ClearErrHandlers()
+		defer ClearErrHandlers()
+
+		AddSyncReasonHandler(func(r reasonForHandlerTester, e Err, tm time.Time) {})
+
+		assert.Len(t, loadErrHandlers().syncHandlers, 1)
+		assert.Empty(t, loadErrHandlers().syncReasonHandlers)
+	})
+
+	t.Run("cannot add any more handlers after fixed", func(t *testing.T) {
+		ClearErrHandlers()
+		defer ClearErrHandlers()
+
+		FixErrHandlers()
+
+		AddSyncReasonHandler(func(r ReasonForHandlerTest, e Err, tm time.Time) {})
+		AddAsyncReasonHandler(func(r ReasonForHandlerTest, e Err, tm time.Time) {})
+
+		assert.Empty(t, loadErrHandlers().syncReasonHandlers)
+		assert.Empty(t, loadErrHandlers().asyncReasonHandlers)
+	})
+}
+
+func TestAddAsyncReasonHandler(t *testing.T) {
+	t.Run("add handlers for a value type and a pointer type", func(t *testing.T) {
+		ClearErrHandlers()
+		defer ClearErrHandlers()
+
+		AddAsyncReasonHandler(func(r ReasonForHandlerTest, e Err, tm time.Time) {})
+		AddAsyncReasonHandler(func(r *ReasonForHandlerTest, e Err, tm time.Time) {})
+
+		assert.Empty(t, loadErrHandlers().asyncHandlers)
+		assert.Len(t, loadErrHandlers().asyncReasonHandlers, 1)
+		assert.Len(t, loadErrHandlers().asyncReasonHandlers[reflect.TypeOf(ReasonForHandlerTest{})], 2)
+		assert.Empty(t, loadErrHandlers().syncReasonHandlers)
+	})
+
+	t.Run("add a handler for an interface type", func(t *testing.T) {
+		ClearErrHandlers()
+		defer ClearErrHandlers()
+
+		AddAsyncReasonHandler(func(r reasonForHandlerTester, e Err, tm time.Time) {})
+
+		assert.Len(t, loadErrHandlers().asyncHandlers, 1)
+		assert.Empty(t, loadErrHandlers().asyncReasonHandlers)
+	})
+}
+
+func TestNotifyErr_reasonHandlers(t *testing.T) {
+	t.Run("notify only handlers for the reason type", func(t *testing.T) {
+		ClearErrHandlers()
+		defer ClearErrHandlers()
+
+		var mu sync.Mutex
+		var logs []string
+		logf := func(format string, a ...any) {
+			mu.Lock()
+			defer mu.Unlock()
+			logs = append(logs, fmt.Sprintf(format, a...))
+		}
+
+		AddSyncReasonHandler(func(r ReasonForHandlerTest, e Err, tm time.Time) {
+			logf("sync-value:%s", r.Name)
+		})
+		AddSyncReasonHandler(func(r *ReasonForHandlerTest, e Err, tm time.Time) {
+			logf("sync-pointer:%s", r.Name)
+		})
+		AddSyncReasonHandler(func(r OtherReasonForHandlerTest, e Err, tm time.Time) {
+			logf("sync-other")
+		})
+		AddSyncReasonHandler(func(r reasonForHandlerTester, e Err, tm time.Time) {
+			logf("sync-interface:%s", r.TestName())
+		})
+		AddAsyncReasonHandler(func(r ReasonForHandlerTest, e Err, tm time.Time) {
+			logf("async-value:%s", r.Name)
+		})
+
+		FixErrHandlers()
+
+		New(ReasonForHandlerTest{Name: "a"})
+		New(&ReasonForHandlerTest{Name: "b"})
+		New(nil)
+		New("c")
+
+		time.Sleep(100 * time.Millisecond)
+
+		mu.Lock()
+		defer mu.Unlock()
+		assert.ElementsMatch(t, logs, []string{
+			"sync-value:a",
+			"sync-pointer:a",
+			"sync-interface:a",
+			"async-value:a",
+			"sync-value:b",
+			"sync-pointer:b",
+			"sync-interface:b",
+			"async-value:b",
+		})
+	})
+
+	t.Run("not notify before fixed", func(t *testing.T) {
+		ClearErrHandlers()
+		defer ClearErrHandlers()
+
+		count := 0
+		AddSyncReasonHandler(func(r ReasonForHandlerTest, e Err, tm time.Time) {
+			count++
+		})
+
+		New(ReasonForHandlerTest{Name: "a"})
+		assert.Equal(t, count, 0)
+
+		FixErrHandlers()
+
+		New(ReasonForHandlerTest{Name: "a"})
+		assert.Equal(t, count, 1)
+	})
+}
+
+func TestSetAsyncErrHandlerPool(t *testing.T) {
+	t.Run("default configuration", func(t *testing.T) {
+		ClearErrHandlers()
+		defer ClearErrHandlers()
+
+		AddAsyncErrHandler(func(e Err, tm time.Time) {})
+		AddAsyncErrHandler(func(e Err, tm time.Time) {})
+		AddAsyncReasonHandler(func(r ReasonForHandlerTest, e Err, tm time.Time) {})
+
+		FixErrHandlers()
+
+		assert.Len(t, loadErrHandlers().pool.queues, 3)
+		assert.Equal(t, cap(loadErrHandlers().pool.queues[0]), 1024)
+		assert.Equal(t, loadErrHandlers().pool.overflow, OverflowBlock)
+	})
+
+	t.Run("limit the number of workers", func(t *testing.T) {
+		ClearErrHandlers()
+		defer ClearErrHandlers()
+
+		SetAsyncErrHandlerPool(2, 10, OverflowDropNewest)
+
+		AddAsyncErrHandler(func(e Err, tm time.Time) {})
+		AddAsyncErrHandler(func(e Err, tm time.Time) {})
+		AddAsyncReasonHandler(func(r ReasonForHandlerTest, e Err, tm time.Time) {})
+
+		FixErrHandlers()
+
+		assert.Len(t, loadErrHandlers().pool.queues, 2)
+		assert.Equal(t, cap(loadErrHandlers().pool.queues[0]), 10)
+		assert.Equal(t, loadErrHandlers().pool.overflow, OverflowDropNewest)
+	})
+
+	t.Run("no worker without async handlers", func(t *testing.T) {
+		ClearErrHandlers()
+		defer ClearErrHandlers()
+
+		AddSyncErrHandler(func(e Err, tm time.Time) {})
+		FixErrHandlers()
+
+		assert.Nil(t, loadErrHandlers().pool)
+	})
+
+	t.Run("cannot configure after fixed", func(t *testing.T) {
+		ClearErrHandlers()
+		defer ClearErrHandlers()
+
+		FixErrHandlers()
+		SetAsyncErrHandlerPool(2, 10, OverflowDropNewest)
+
+		assert.Equal(t, asyncErrHandlerWorkers, 0)
+		assert.Equal(t, asyncErrHandlerQueueSize, 1024)
+		assert.Equal(t, asyncErrHandlerOverflow, OverflowBlock)
+	})
+}
+
+func TestErrHandlerPool(t *testing.T) {
+	t.Run("keep the order of invocations per handler", func(t *testing.T) {
+		ClearErrHandlers()
+		defer ClearErrHandlers()
+
+		SetAsyncErrHandlerPool(1, 1, OverflowBlock)
+
+		var mu sync.Mutex
+		var logs []string
+		AddAsyncReasonHandler(func(r ReasonForHandlerTest, e Err, tm time.Time) {
+			time.Sleep(time.Millisecond)
+			mu.Lock()
+			defer mu.Unlock()
+			logs = append(logs, "a:"+r.Name)
+		})
+		AddAsyncReasonHandler(func(r ReasonForHandlerTest, e Err, tm time.Time) {
+			mu.Lock()
+			defer mu.Unlock()
+			logs = append(logs, "b:"+r.Name)
+		})
+
+		FixErrHandlers()
+
+		for i := 0; i < 5; i++ {
+			New(ReasonForHandlerTest{Name: fmt.Sprint(i)})
+		}
+
+		time.Sleep(100 * time.Millisecond)
+
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, logs, []string{
+			"a:0", "b:0", "a:1", "b:1", "a:2", "b:2", "a:3", "b:3", "a:4", "b:4",
+		})
+		assert.Equal(t, DroppedErrNotifications(), uint64(0))
+	})
+
+	t.Run("not block a worker creating an Err with its own queue full", func(t *testing.T) {
+		ClearErrHandlers()
+		defer ClearErrHandlers()
+
+		SetAsyncErrHandlerPool(1, 1, OverflowBlock)
+
+		var count int32
+		AddAsyncReasonHandler(func(r ReasonForHandlerTest, e Err, tm time.Time) {
+			for i := 0; i < 3; i++ {
+				New(OtherReasonForHandlerTest{})
+			}
+		})
+		AddAsyncReasonHandler(func(r OtherReasonForHandlerTest, e Err, tm time.Time) {
+			atomic.AddInt32(&count, 1)
+		})
+
+		FixErrHandlers()
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			New(ReasonForHandlerTest{Name: "a"})
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			assert.Nil(t, FlushErrHandlers(ctx))
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(2 * time.Second):
+			t.Fatal("deadlocked")
+		}
+		assert.Equal(t, atomic.LoadInt32(&count), int32(1))
+		assert.Equal(t, DroppedErrNotifications(), uint64(2))
+	})
+
+	t.Run("drop newest", func(t *testing.T) {
+		ClearErrHandlers()
+		defer ClearErrHandlers()
+
+		SetAsyncErrHandlerPool(1, 2, OverflowDropNewest)
+
+		block := make(chan struct{})
+		var mu sync.Mutex
+		var logs []string
+		AddAsyncReasonHandler(func(r ReasonForHandlerTest, e Err, tm time.Time) {
+			<-block
+			mu.Lock()
+			defer mu.Unlock()
+			logs = append(logs, r.Name)
+		})
+
+		FixErrHandlers()
+
+		New(ReasonForHandlerTest{Name: "0"})
+		time.Sleep(50 * time.Millisecond) // wait until the worker takes the first job.
+		for i := 1; i < 5; i++ {
+			New(ReasonForHandlerTest{Name: fmt.Sprint(i)})
+		}
+		close(block)
+
+		time.Sleep(100 * time.Millisecond)
+
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, logs, []string{"0", "1", "2"})
+		assert.Equal(t, DroppedErrNotifications(), uint64(2))
+	})
+
+	t.Run("drop oldest", func(t *testing.T) {
+		ClearErrHandlers()
+		defer ClearErrHandlers()
+
+		SetAsyncErrHandlerPool(1, 2, OverflowDropOldest)
+
+		block := make(chan struct{})
+		var mu sync.Mutex
+		var logs []string
+		AddAsyncReasonHandler(func(r ReasonForHandlerTest, e Err, tm time.Time) {
+			<-block
+			mu.Lock()
+			defer mu.Unlock()
+			logs = append(logs, r.Name)
+		})
+
+		FixErrHandlers()
+
+		New(ReasonForHandlerTest{Name: "0"})
+		time.Sleep(50 * time.Millisecond) // wait until the worker takes the first job.
+		for i := 1; i < 5; i++ {
+			New(ReasonForHandlerTest{Name: fmt.Sprint(i)})
+		}
+		close(block)
+
+		time.Sleep(100 * time.Millisecond)
+
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, logs, []string{"0", "3", "4"})
+		assert.Equal(t, DroppedErrNotifications(), uint64(2))
+	})
+}
+
+func TestFlushErrHandlers(t *testing.T) {
+	t.Run("no async handler", func(t *testing.T) {
+		ClearErrHandlers()
+		defer ClearErrHandlers()
+
+		AddSyncErrHandler(func(e Err, tm time.Time) {})
+		FixErrHandlers()
+
+		New(ReasonForHandlerTest{Name: "a"})
+		assert.Nil(t, FlushErrHandlers(context.Background()))
+	})
+
+	t.Run("wait for pending invocations", func(t *testing.T) {
+		ClearErrHandlers()
+		defer ClearErrHandlers()
+
+		var mu sync.Mutex
+		var logs []string
+		AddAsyncReasonHandler(func(r ReasonForHandlerTest, e Err, tm time.Time) {
+			time.Sleep(10 * time.Millisecond)
+			mu.Lock()
+			defer mu.Unlock()
+			logs = append(logs, r.Name)
+		})
+		FixErrHandlers()
+
+		New(ReasonForHandlerTest{Name: "a"})
+		New(ReasonForHandlerTest{Name: "b"})
+		New(ReasonForHandlerTest{Name: "c"})
+
+		assert.Nil(t, FlushErrHandlers(context.Background()))
+
+		mu.Lock()
+		assert.Equal(t, logs, []string{"a", "b", "c"})
+		mu.Unlock()
+
+		New(ReasonForHandlerTest{Name: "d"})
+		assert.Nil(t, FlushErrHandlers(context.Background()))
+
+		mu.Lock()
+		assert.Equal(t, logs, []string{"a", "b", "c", "d"})
+		mu.Unlock()
+	})
+
+	t.Run("context is done before finishing", func(t *testing.T) {
+		ClearErrHandlers()
+		defer ClearErrHandlers()
+
+		block := make(chan struct{})
+		defer close(block)
+		AddAsyncErrHandler(func(e Err, tm time.Time) {
+			<-block
+		})
+		FixErrHandlers()
+
+		New(ReasonForHandlerTest{Name: "a"})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.Equal(t, FlushErrHandlers(ctx), context.DeadlineExceeded)
+	})
+}
+
+func TestShutdownErrHandlers(t *testing.T) {
+	t.Run("wait for pending invocations and stop notifying", func(t *testing.T) {
+		ClearErrHandlers()
+		defer ClearErrHandlers()
+
+		var mu sync.Mutex
+		var logs []string
+		AddSyncReasonHandler(func(r ReasonForHandlerTest, e Err, tm time.Time) {
+			mu.Lock()
+			defer mu.Unlock()
+			logs = append(logs, "sync:"+r.Name)
+		})
+		AddAsyncReasonHandler(func(r ReasonForHandlerTest, e Err, tm time.Time) {
+			time.Sleep(10 * time.Millisecond)
+			mu.Lock()
+			defer mu.Unlock()
+			logs = append(logs, "async:"+r.Name)
+		})
+		FixErrHandlers()
+
+		New(ReasonForHandlerTest{Name: "a"})
+		New(ReasonForHandlerTest{Name: "b"})
+
+		assert.Nil(t, ShutdownErrHandlers(context.Background()))
+
+		New(ReasonForHandlerTest{Name: "c"})
+		time.Sleep(50 * time.Millisecond)
+
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, logs, []string{"sync:a", "sync:b", "async:a", "async:b"})
+
+		assert.Nil(t, ShutdownErrHandlers(context.Background()))
+	})
+
+	t.Run("shutdown before fixed", func(t *testing.T) {
+		ClearErrHandlers()
+		defer ClearErrHandlers()
+
+		count := 0
+		AddSyncErrHandler(func(e Err, tm time.Time) {
+			count++
+		})
+
+		assert.Nil(t, ShutdownErrHandlers(context.Background()))
+		assert.True(t, isErrHandlersFixed())
+
+		FixErrHandlers()
+		New(ReasonForHandlerTest{Name: "a"})
+		assert.Equal(t, count, 0)
+	})
+
+	t.Run("context is done before finishing", func(t *testing.T) {
+		ClearErrHandlers()
+		defer ClearErrHandlers()
+
+		block := make(chan struct{})
+		defer close(block)
+		AddAsyncErrHandler(func(e Err, tm time.Time) {
+			<-block
+		})
+		FixErrHandlers()
+
+		New(ReasonForHandlerTest{Name: "a"})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.Equal(t, ShutdownErrHandlers(ctx), context.DeadlineExceeded)
+
+		select {
+		case <-loadErrHandlers().pool.done:
+		default:
+			assert.Fail(t, "workers are not stopped")
+		}
+	})
+
+	t.Run("flush after shutdown timed out", func(t *testing.T) {
+		ClearErrHandlers()
+		defer ClearErrHandlers()
+
+		block := make(chan struct{})
+		AddAsyncErrHandler(func(e Err, tm time.Time) {
+			<-block
+		})
+		FixErrHandlers()
+
+		New(ReasonForHandlerTest{Name: "a"})
+		New(ReasonForHandlerTest{Name: "b"})
+		New(ReasonForHandlerTest{Name: "c"})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.Equal(t, ShutdownErrHandlers(ctx), context.DeadlineExceeded)
+
+		pool := loadErrHandlers().pool
+		close(block)
+
+		assert.Nil(t, FlushErrHandlers(context.Background()))
+		assert.Nil(t, pool.flush(context.Background()))
+		assert.Equal(t, atomic.LoadInt64(&pool.pending), int64(0))
+	})
+
+	t.Run("enqueue after the pool is stopped", func(t *testing.T) {
+		pool := newErrHandlerPool(1)
+		handler := pool.bind(func(e Err, tm time.Time) {})
+		pool.stop()
+
+		handler(Err{}, time.Now())
+		assert.Equal(t, atomic.LoadInt64(&pool.pending), int64(0))
+
+		pool.enqueue(pool.queues[0], errHandlerJob{handler: func(Err, time.Time) {}})
+		assert.Equal(t, atomic.LoadInt64(&pool.pending), int64(0))
+		assert.Len(t, pool.queues[0], 0)
+	})
+}
+
+func TestErrHandlerPanic(t *testing.T) {
+	t.Run("a panic in a sync handler does not propagate", func(t *testing.T) {
+		ClearErrHandlers()
+		defer ClearErrHandlers()
+
+		var panics []ErrHandlerPanic
+		SetErrHandlerPanicHook(func(p ErrHandlerPanic) {
+			panics = append(panics, p)
+		})
+
+		count := 0
+		AddSyncErrHandler(func(e Err, tm time.Time) {
+			panic("sync handler panicked")
+		})
+		AddSyncErrHandler(func(e Err, tm time.Time) {
+			count++
+		})
+		FixErrHandlers()
+
+		assert.NotPanics(t, func() {
+			New(ReasonForHandlerTest{Name: "a"})
+		})
+		assert.Equal(t, count, 1)
+
+		assert.Len(t, panics, 1)
+		assert.Equal(t, panics[0].Value, "sync handler panicked")
+		assert.Equal(t, panics[0].Err.Reason(), ReasonForHandlerTest{Name: "a"})
+		assert.False(t, panics[0].Time.IsZero())
+		assert.Contains(t, string(panics[0].Stack), "notify_test.go")
+		assert.False(t, panics[0].Disabled)
+	})
+
+	t.Run("a panic in an async handler does not kill the worker", func(t *testing.T) {
+		ClearErrHandlers()
+		defer ClearErrHandlers()
+
+		var mu sync.Mutex
+		var panics []ErrHandlerPanic
+		SetErrHandlerPanicHook(func(p ErrHandlerPanic) {
+			mu.Lock()
+			defer mu.Unlock()
+			panics = append(panics, p)
+		})
+
+		var names []string
+		AddAsyncReasonHandler(func(r ReasonForHandlerTest, e Err, tm time.Time) {
+			if r.Name == "bad" {
+				panic(r.Name)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			names = append(names, r.Name)
+		})
+		FixErrHandlers()
+
+		New(ReasonForHandlerTest{Name: "a"})
+		New(ReasonForHandlerTest{Name: "bad"})
+		New(ReasonForHandlerTest{Name: "b"})
+
+		assert.Nil(t, FlushErrHandlers(context.Background()))
+
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, names, []string{"a", "b"})
+		assert.Len(t, panics, 1)
+		assert.Equal(t, panics[0].Value, "bad")
+	})
+
+	t.Run("disable a handler after consecutive panics", func(t *testing.T) {
+		ClearErrHandlers()
+		defer ClearErrHandlers()
+
+		var panics []ErrHandlerPanic
+		SetErrHandlerPanicHook(func(p ErrHandlerPanic) {
+			panics = append(panics, p)
+		})
+		SetErrHandlerPanicLimit(2)
+
+		calls := 0
+		AddSyncReasonHandler(func(r ReasonForHandlerTest, e Err, tm time.Time) {
+			calls++
+			if r.Name == "bad" {
+				panic(r.Name)
+			}
+		})
+		FixErrHandlers()
+
+		New(ReasonForHandlerTest{Name: "bad"})
+		New(ReasonForHandlerTest{Name: "ok"}) // resets the count of consecutive panics.
+		New(ReasonForHandlerTest{Name: "bad"})
+		New(ReasonForHandlerTest{Name: "bad"})
+		New(ReasonForHandlerTest{Name: "ok"})
+		New(ReasonForHandlerTest{Name: "bad"})
+
+		assert.Equal(t, calls, 4)
+		assert.Len(t, panics, 3)
+		assert.False(t, panics[0].Disabled)
+		assert.False(t, panics[1].Disabled)
+		assert.True(t, panics[2].Disabled)
+	})
+
+	t.Run("a panic in the hook is ignored", func(t *testing.T) {
+		ClearErrHandlers()
+		defer ClearErrHandlers()
+
+		SetErrHandlerPanicHook(func(p ErrHandlerPanic) {
+			panic("hook panicked")
+		})
+		AddSyncErrHandler(func(e Err, tm time.Time) {
+			panic("sync handler panicked")
+		})
+		FixErrHandlers()
+
+		assert.NotPanics(t, func() {
+			New(ReasonForHandlerTest{Name: "a"})
+		})
+	})
+
+	t.Run("report to stderr by default", func(t *testing.T) {
+		ClearErrHandlers()
+		defer ClearErrHandlers()
+
+		var buf bytes.Buffer
+		errHandlerPanicOut = &buf
+		defer func() { errHandlerPanicOut = os.Stderr }()
+
+		SetErrHandlerPanicLimit(1)
+		AddSyncErrHandler(func(e Err, tm time.Time) {
+			panic("sync handler panicked")
+		})
+		FixErrHandlers()
+
+		New(ReasonForHandlerTest{Name: "a"})
+		assert.True(t, strings.HasPrefix(buf.String(), "errs: error handler panicked and was disabled: sync handler panicked\n"))
+	})
+
+	t.Run("cannot configure after fixed", func(t *testing.T) {
+		ClearErrHandlers()
+		defer ClearErrHandlers()
+
+		FixErrHandlers()
+		SetErrHandlerPanicHook(func(p ErrHandlerPanic) {})
+		SetErrHandlerPanicLimit(3)
+
+		assert.Equal(t, errHandlerPanicLimit, 0)
+	})
+}
+
+func TestErrHandlers_concurrentRegistrationAndNotification(t *testing.T) {
+	ClearErrHandlers()
+	defer ClearErrHandlers()
+
+	var count int64
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					New(ReasonForHandlerTest{Name: "a"})
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < 10; i++ {
+		AddSyncErrHandler(func(e Err, tm time.Time) {
+			atomic.AddInt64(&count, 1)
+		})
+		AddSyncReasonHandler(func(r ReasonForHandlerTest, e Err, tm time.Time) {
+			atomic.AddInt64(&count, 1)
+		})
+	}
+	FixErrHandlers()
+	AddSyncErrHandler(func(e Err, tm time.Time) {})
+
+	close(stop)
+	wg.Wait()
+
+	assert.Len(t, loadErrHandlers().syncHandlers, 10)
+	assert.Len(t, loadErrHandlers().syncReasonHandlers[reflect.TypeOf(ReasonForHandlerTest{})], 10)
+
+	before := atomic.LoadInt64(&count)
+	New(ReasonForHandlerTest{Name: "b"})
+	assert.Equal(t, atomic.LoadInt64(&count), before+20)
+}
+
+func TestErrNotification_enableAndDisable(t *testing.T) {
+	t.Run("enabled or disabled by the environment variable", func(t *testing.T) {
+		assert.True(t, isErrNotificationEnabledBy("1", false))
+		assert.True(t, isErrNotificationEnabledBy("true", false))
+		assert.True(t, isErrNotificationEnabledBy(" ON ", false))
+		assert.False(t, isErrNotificationEnabledBy("0", true))
+		assert.False(t, isErrNotificationEnabledBy("False", true))
+		assert.False(t, isErrNotificationEnabledBy("off", true))
+		assert.True(t, isErrNotificationEnabledBy("", true))
+		assert.False(t, isErrNotificationEnabledBy("", false))
+		assert.False(t, isErrNotificationEnabledBy("xxx", false))
+	})
+
+	t.Run("switch the notification at runtime", func(t *testing.T) {
+		ClearErrHandlers()
+		defer ClearErrHandlers()
+
+		var count int
+		AddSyncErrHandler(func(e Err, tm time.Time) {
+			count++
+		})
+		FixErrHandlers()
+
+		DisableErrNotification()
+		assert.False(t, IsErrNotificationEnabled())
+		New(ReasonForHandlerTest{Name: "a"})
+		assert.Equal(t, count, 0)
+
+		EnableErrNotification()
+		assert.True(t, IsErrNotificationEnabled())
+		New(ReasonForHandlerTest{Name: "b"})
+		assert.Equal(t, count, 1)
+
+		DisableErrNotification()
+		New(ReasonForHandlerTest{Name: "c"})
+		assert.Equal(t, count, 1)
+	})
+
+	t.Run("register handlers while disabled", func(t *testing.T) {
+		ClearErrHandlers()
+		defer ClearErrHandlers()
+
+		DisableErrNotification()
+
+		var count int
+		AddSyncErrHandler(func(e Err, tm time.Time) {
+			count++
+		})
+		FixErrHandlers()
+		assert.Len(t, loadErrHandlers().syncHandlers, 1)
+
+		New(ReasonForHandlerTest{Name: "a"})
+		assert.Equal(t, count, 0)
+
+		EnableErrNotification()
+		New(ReasonForHandlerTest{Name: "b"})
+		assert.Equal(t, count, 1)
+	})
+
+	t.Run("not notify after shutdown even if enabled", func(t *testing.T) {
+		ClearErrHandlers()
+		defer ClearErrHandlers()
+
+		var count int
+		AddSyncErrHandler(func(e Err, tm time.Time) {
+			count++
+		})
+		FixErrHandlers()
+		assert.Nil(t, ShutdownErrHandlers(context.Background()))
+
+		EnableErrNotification()
+		New(ReasonForHandlerTest{Name: "a"})
+		assert.Equal(t, count, 0)
+	})
+}
+
+func TestNotifyErr_panicked(t *testing.T) {
+	ClearErrHandlers()
+	defer ClearErrHandlers()
+
+	var got []Err
+	AddSyncReasonHandler(func(r Panicked, e Err, tm time.Time) {
+		got = append(got, e)
+	})
+	FixErrHandlers()
+
+	err := Safe(func() Err {
+		panic("boom")
+	})
+	assert.Len(t, got, 1)
+	assert.Equal(t, got[0], err)
+	assert.Equal(t, got[0].Reason(), Panicked{Value: "boom"})
 }
@@ -0,0 +1,68 @@
+// Copyright (C) 2025-2026 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package errs
+
+// Panicked is the reason of an Err which is converted from a panic by RecoverPanic, Safe or Go.
+// Value is the value passed to panic.
+// If the value is an error, it is also the cause of the Err.
+type Panicked struct {
+	Value any
+}
+
+// RecoverPanic recovers from a panic and sets an Err converted from the panic to the variable
+// pointed to by the argument. This function must be called directly with a defer statement.
+// If the current goroutine is not panicking, this function does nothing.
+//
+// The reason of the Err is Panicked, and its location and stack trace are those of the panic site,
+// not of this function. The Err is notified to the error handlers like other Err(s).
+// (This function is not named Recover, since that name is used for the function which handles
+// an Err with a specific reason.)
+//
+//	func doSomething() (err errs.Err) {
+//	    defer errs.RecoverPanic(&err)
+//	    ...
+//	}
+func RecoverPanic(errp *Err) {
+	if r := recover(); r != nil {
+		e := newPanickedErr(r, 1)
+		if errp != nil {
+			*errp = e
+		}
+	}
+}
+
+// Safe executes the provided function and returns the Err which the function returns.
+// If the function panics, this function recovers from it and returns an Err converted from the
+// panic, like RecoverPanic.
+func Safe(fn func() Err) (err Err) {
+	defer RecoverPanic(&err)
+	return fn()
+}
+
+// Go executes the provided function in a new goroutine, and returns a channel which receives the
+// Err which the function returns, and then is closed.
+// If the function panics, the channel receives an Err converted from the panic, like
+// RecoverPanic, instead of crashing the program.
+func Go(fn func() Err) <-chan Err {
+	ch := make(chan Err, 1)
+	go func() {
+		defer close(ch)
+		ch <- Safe(fn)
+	}()
+	return ch
+}
+
+func newPanickedErr(value any, skip int) Err {
+	var e Err
+	e.reason = Panicked{Value: value}
+	if cause, ok := value.(error); ok {
+		e.cause = cause
+	}
+	e.file, e.line, e.stack = capturePanicSite(skip + 1)
+
+	notifyErr(e)
+
+	return e
+}
@@ -0,0 +1,166 @@
+package errs_test
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/errs"
+)
+
+func panicForPanicTest(v any) {
+	panic(v)
+}
+
+func nilMapWriteForPanicTest() {
+	var m map[string]int
+	m["a"] = 1
+}
+
+func TestRecoverPanic(t *testing.T) {
+	t.Run("not panicking", func(t *testing.T) {
+		f := func() (err errs.Err) {
+			defer errs.RecoverPanic(&err)
+			return errs.New(InvalidValue{Name: "abc"})
+		}
+		err := f()
+		assert.Equal(t, err.Reason(), InvalidValue{Name: "abc"})
+	})
+
+	t.Run("panic with a non-error value", func(t *testing.T) {
+		f := func() (err errs.Err) {
+			defer errs.RecoverPanic(&err)
+			panicForPanicTest("boom")
+			return errs.Ok()
+		}
+		err := f()
+		assert.True(t, err.IsNotOk())
+		assert.Equal(t, err.Reason(), errs.Panicked{Value: "boom"})
+		assert.Nil(t, err.Cause())
+		assert.Equal(t, err.File(), "panic_test.go")
+		assert.Equal(t, err.Line(), 15)
+		assert.Nil(t, err.StackTrace())
+		assert.Equal(t, fmt.Sprintf("%v", err), "github.com/sttk/errs.Panicked{Value:boom}")
+	})
+
+	t.Run("panic with an error", func(t *testing.T) {
+		cause := errors.New("abc")
+		f := func() (err errs.Err) {
+			defer errs.RecoverPanic(&err)
+			panicForPanicTest(cause)
+			return errs.Ok()
+		}
+		err := f()
+		assert.Equal(t, err.Reason(), errs.Panicked{Value: cause})
+		assert.Equal(t, err.Cause(), cause)
+		assert.True(t, errors.Is(err, cause))
+		assert.Equal(t, err.Line(), 15)
+	})
+
+	t.Run("runtime error", func(t *testing.T) {
+		f := func() (err errs.Err) {
+			defer errs.RecoverPanic(&err)
+			nilMapWriteForPanicTest()
+			return errs.Ok()
+		}
+		err := f()
+		reason, ok := err.Reason().(errs.Panicked)
+		assert.True(t, ok)
+		_, ok = reason.Value.(runtime.Error)
+		assert.True(t, ok)
+		var re runtime.Error
+		assert.True(t, errors.As(err, &re))
+		assert.Equal(t, err.File(), "panic_test.go")
+		assert.Equal(t, err.Line(), 20)
+	})
+
+	t.Run("stack trace of the panic site", func(t *testing.T) {
+		errs.SetStackTraceDepth(32)
+		defer errs.SetStackTraceDepth(0)
+
+		f := func() (err errs.Err) {
+			defer errs.RecoverPanic(&err)
+			panicForPanicTest("boom")
+			return errs.Ok()
+		}
+		err := f()
+		frames := err.StackTrace()
+		assert.True(t, len(frames) >= 2)
+		assert.Equal(t, frames[0].Function, "github.com/sttk/errs_test.panicForPanicTest")
+		assert.Equal(t, frames[0].Line, 15)
+		assert.True(t, strings.HasPrefix(frames[1].Function, "github.com/sttk/errs_test.TestRecoverPanic"))
+		assert.Equal(t, frames[1].Line, 86)
+	})
+
+	t.Run("nil pointer", func(t *testing.T) {
+		func() {
+			defer errs.RecoverPanic(nil)
+			panicForPanicTest("boom")
+		}()
+	})
+}
+
+func TestSafe(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		err := errs.Safe(func() errs.Err {
+			return errs.Ok()
+		})
+		assert.True(t, err.IsOk())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		err := errs.Safe(func() errs.Err {
+			return errs.New(InvalidValue{Name: "abc"})
+		})
+		assert.Equal(t, err.Reason(), InvalidValue{Name: "abc"})
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		err := errs.Safe(func() errs.Err {
+			panicForPanicTest(123)
+			return errs.Ok()
+		})
+		assert.Equal(t, err.Reason(), errs.Panicked{Value: 123})
+		assert.Equal(t, err.Line(), 15)
+	})
+
+	t.Run("panic with an Err", func(t *testing.T) {
+		err0 := errs.New(InvalidValue{Name: "abc"})
+		err := errs.Safe(func() errs.Err {
+			errs.ErrResult[int](err0).Unwrap()
+			return errs.Ok()
+		})
+		assert.Equal(t, err.Reason(), errs.Panicked{Value: err0})
+		assert.Equal(t, err.Cause(), err0)
+		assert.True(t, errs.HasReason[InvalidValue](err))
+	})
+}
+
+func TestGo(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		ch := errs.Go(func() errs.Err {
+			return errs.Ok()
+		})
+		err, ok := <-ch
+		assert.True(t, ok)
+		assert.True(t, err.IsOk())
+		_, ok = <-ch
+		assert.False(t, ok)
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		ch := errs.Go(func() errs.Err {
+			panicForPanicTest(fmt.Errorf("abc"))
+			return errs.Ok()
+		})
+		err := <-ch
+		assert.Equal(t, err.Cause().Error(), "abc")
+		assert.Equal(t, err.File(), "panic_test.go")
+		assert.Equal(t, err.Line(), 15)
+		_, ok := <-ch
+		assert.False(t, ok)
+	})
+}
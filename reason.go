@@ -0,0 +1,219 @@
+// Copyright (C) 2025-2026 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package errs
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ReasonOf finds the first Err in the chain of the specified error whose reason is of the type
+// parameter T, and returns the reason.
+// A reason of the pointer type to T, or a reason of the pointed type if T is a pointer type, also
+// matches, and it is returned after being converted to T.
+//
+// The chain consists of the error itself and the errors obtained by repeatedly calling its
+// Unwrap() error or Unwrap() []error method, or the Causes method for an Err, and it is searched
+// in a depth-first order like errors.As.
+// If no reason is found, this function returns the zero value of T and false.
+func ReasonOf[T any](err error) (T, bool) {
+	var zero T
+	if err == nil {
+		return zero, false
+	}
+
+	var e Err
+	switch x := err.(type) {
+	case Err:
+		e = x
+	case *Err:
+		if x != nil {
+			e = *x
+		}
+	}
+	if e.IsNotOk() {
+		if r, ok := castReason[T](e.reason); ok {
+			return r, true
+		}
+		for _, c := range e.Causes() {
+			if r, ok := ReasonOf[T](c); ok {
+				return r, true
+			}
+		}
+		return zero, false
+	}
+
+	switch x := err.(type) {
+	case interface{ Unwrap() error }:
+		return ReasonOf[T](x.Unwrap())
+	case interface{ Unwrap() []error }:
+		for _, c := range x.Unwrap() {
+			if r, ok := ReasonOf[T](c); ok {
+				return r, true
+			}
+		}
+	}
+	return zero, false
+}
+
+// HasReason reports whether the chain of the specified error contains an Err whose reason is of
+// the type parameter T.
+// The chain is searched in the same way as ReasonOf.
+func HasReason[T any](err error) bool {
+	_, ok := ReasonOf[T](err)
+	return ok
+}
+
+// ReasonType returns the type by which the reason type T is identified. If T is a pointer type,
+// this returns the pointed type, so T and *T are identified as the same reason type, in the same
+// way as ReasonOf and the reason handlers.
+// This function is useful for a registry keyed by reason types.
+func ReasonType[T any]() reflect.Type {
+	return reasonKey(reflect.TypeOf((*T)(nil)).Elem())
+}
+
+// ReasonTypeOf returns the type by which the specified reason is identified, which is the pointed
+// type if the reason is a pointer. If the reason is nil, this function returns nil.
+func ReasonTypeOf(reason any) reflect.Type {
+	return reasonKey(reflect.TypeOf(reason))
+}
+
+// Target creates an error with the specified reason to be used as the target of errors.Is.
+// Unlike New, this function neither records the location nor captures the stack trace, and the
+// created error is not notified to the error handlers. So a comparison with errors.Is does not
+// cost the notification, and is not regarded as an error by the handlers.
+//
+//	if errors.Is(err, errs.Target(NotFound{})) {
+//	    ...
+//	}
+//
+// The created error is a pointer, so errors.Is can compare it with == safely even if the reason
+// holds a slice, a map or a function. On the other hand, using an Err created with New as the
+// target, such as errors.Is(err, errs.New(NotFound{})), panics when errors.Is compares the target
+// with an Err in the chain and either of their reasons holds such a value.
+func Target(reason any) error {
+	return &reasonTarget{reason: reason}
+}
+
+// reasonTarget is the type of the errors which Target creates, and is recognized by Err.Is.
+type reasonTarget struct {
+	reason any
+}
+
+func (t *reasonTarget) Error() string {
+	if t.reason == nil {
+		return "github.com/sttk/errs.Target {}"
+	}
+	return fmt.Sprintf("github.com/sttk/errs.Target {reason:%s}", reasonString(t.reason))
+}
+
+// Is reports whether the target is an Err, or an error created by Target, whose reason matches
+// the reason of this Err, so that errors.Is can check the reason of an Err in an error chain.
+//
+// The reasons match if their types are the same, regardless of whether they are pointers or not.
+// In addition, if the target's reason is not the zero value of its type, the reasons must be equal
+// with ==. So a target created with the zero value of a reason type, such as
+// errs.Target(NotFound{}), matches any Err with a reason of that type.
+// Reasons which cannot be compared with ==, because their type is incomparable or they hold
+// incomparable values in interface fields, are never equal. So a non-zero target with such a
+// reason matches nothing, even an Err with the same reason; use a zero-valued target for them.
+// The locations and the causes are not compared, and an Err which indicates no error matches
+// nothing.
+//
+// On Go 1.18 and 1.19, where Unwrap returns only the first cause, this method also reports
+// whether any of the other causes matches the target.
+func (e Err) Is(target error) bool {
+	var t Err
+	switch x := target.(type) {
+	case Err:
+		t = x
+	case *Err:
+		if x != nil {
+			t = *x
+		}
+	case *reasonTarget:
+		if x != nil {
+			t = Err{reason: x.reason}
+		}
+	}
+	if e.IsNotOk() && t.IsNotOk() && matchReason(e.reason, t.reason) {
+		return true
+	}
+	return e.isInOtherCauses(target)
+}
+
+func matchReason(reason, target any) bool {
+	if reasonKey(reflect.TypeOf(reason)) != reasonKey(reflect.TypeOf(target)) {
+		return false
+	}
+
+	tv := derefReason(reflect.ValueOf(target))
+	if !tv.IsValid() || tv.IsZero() {
+		return true
+	}
+	rv := derefReason(reflect.ValueOf(reason))
+	if !rv.IsValid() || !tv.Type().Comparable() {
+		return false
+	}
+	return equalReasons(rv.Interface(), tv.Interface())
+}
+
+func derefReason(v reflect.Value) reflect.Value {
+	if v.Kind() == reflect.Ptr {
+		return v.Elem()
+	}
+	return v
+}
+
+// equalReasons compares the reasons with ==, and returns false if the comparison panics because
+// the reasons contain incomparable values in interface fields.
+func equalReasons(a, b any) (eq bool) {
+	defer func() {
+		if recover() != nil {
+			eq = false
+		}
+	}()
+	return a == b
+}
+
+// As sets the reason of this Err to the target and returns true if the reason can be assigned to
+// the value pointed by the target, so that errors.As can extract the reason of an Err in an error
+// chain.
+// A reason of the pointer type or the pointed type of the target's type is also set after being
+// converted.
+//
+// On Go 1.18 and 1.19, where Unwrap returns only the first cause, this method also tries the
+// other causes.
+//
+// Note that errors.As panics if the type pointed by the target is neither an interface nor an
+// error type, so a reason type which does not implement error cannot be extracted with errors.As.
+// Use ReasonOf for such reason types.
+func (e Err) As(target any) bool {
+	if e.IsOk() || target == nil {
+		return false
+	}
+	tv := reflect.ValueOf(target)
+	if tv.Kind() != reflect.Ptr || tv.IsNil() {
+		return false
+	}
+	dst := tv.Elem()
+
+	rv := reflect.ValueOf(e.reason)
+	if rv.Type().AssignableTo(dst.Type()) {
+		dst.Set(rv)
+		return true
+	}
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() && rv.Elem().Type().AssignableTo(dst.Type()) {
+		dst.Set(rv.Elem())
+		return true
+	}
+	if dst.Kind() == reflect.Ptr && dst.Type().Elem() == rv.Type() {
+		p := reflect.New(rv.Type())
+		p.Elem().Set(rv)
+		dst.Set(p)
+		return true
+	}
+	return e.asInOtherCauses(target)
+}
@@ -0,0 +1,260 @@
+package errs_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/errs"
+)
+
+type multiError []error
+
+func (m multiError) Error() string {
+	return fmt.Sprintf("%d errors", len(m))
+}
+
+func (m multiError) Unwrap() []error {
+	return m
+}
+
+type reasonNameGetter interface {
+	GetName() string
+}
+
+func (r FailToGetValue) GetName() string {
+	return r.Name
+}
+
+func TestReasonOf(t *testing.T) {
+	t.Run("nil", func(t *testing.T) {
+		r, ok := errs.ReasonOf[InvalidValue](nil)
+		assert.False(t, ok)
+		assert.Equal(t, r, InvalidValue{})
+	})
+
+	t.Run("ok", func(t *testing.T) {
+		r, ok := errs.ReasonOf[InvalidValue](errs.Ok())
+		assert.False(t, ok)
+		assert.Equal(t, r, InvalidValue{})
+	})
+
+	t.Run("the error itself", func(t *testing.T) {
+		err := errs.New(InvalidValue{Name: "foo", Value: "abc"})
+		r, ok := errs.ReasonOf[InvalidValue](err)
+		assert.True(t, ok)
+		assert.Equal(t, r, InvalidValue{Name: "foo", Value: "abc"})
+	})
+
+	t.Run("pointer and value variants", func(t *testing.T) {
+		err := errs.New(&InvalidValue{Name: "foo", Value: "abc"})
+		r, ok := errs.ReasonOf[InvalidValue](err)
+		assert.True(t, ok)
+		assert.Equal(t, r, InvalidValue{Name: "foo", Value: "abc"})
+
+		err = errs.New(InvalidValue{Name: "foo", Value: "abc"})
+		p, ok := errs.ReasonOf[*InvalidValue](err)
+		assert.True(t, ok)
+		assert.Equal(t, p, &InvalidValue{Name: "foo", Value: "abc"})
+	})
+
+	t.Run("a pointer to Err", func(t *testing.T) {
+		err := errs.New(InvalidValue{Name: "foo"})
+		r, ok := errs.ReasonOf[InvalidValue](&err)
+		assert.True(t, ok)
+		assert.Equal(t, r.Name, "foo")
+	})
+
+	t.Run("in the cause chain", func(t *testing.T) {
+		cause := errs.New(FailToGetValue{Name: "bar"}, errors.New("def"))
+		err := fmt.Errorf("wrapped: %w", errs.New(InvalidValue{Name: "foo"}, cause))
+
+		r, ok := errs.ReasonOf[FailToGetValue](err)
+		assert.True(t, ok)
+		assert.Equal(t, r, FailToGetValue{Name: "bar"})
+	})
+
+	t.Run("the first matching reason", func(t *testing.T) {
+		cause := errs.New(InvalidValue{Name: "inner"})
+		err := errs.New(InvalidValue{Name: "outer"}, cause)
+
+		r, ok := errs.ReasonOf[InvalidValue](err)
+		assert.True(t, ok)
+		assert.Equal(t, r.Name, "outer")
+	})
+
+	t.Run("in multi-errors", func(t *testing.T) {
+		err := multiError{
+			errors.New("abc"),
+			errs.New(InvalidValue{Name: "foo"}),
+			errs.New(FailToGetValue{Name: "bar"}),
+		}
+
+		r, ok := errs.ReasonOf[FailToGetValue](err)
+		assert.True(t, ok)
+		assert.Equal(t, r, FailToGetValue{Name: "bar"})
+
+		_, ok = errs.ReasonOf[string](err)
+		assert.False(t, ok)
+	})
+
+	t.Run("interface type", func(t *testing.T) {
+		err := errs.New(InvalidValue{Name: "foo"}, errs.New(FailToGetValue{Name: "bar"}))
+
+		r, ok := errs.ReasonOf[reasonNameGetter](err)
+		assert.True(t, ok)
+		assert.Equal(t, r.GetName(), "bar")
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		err := fmt.Errorf("wrapped: %w", errors.New("abc"))
+		_, ok := errs.ReasonOf[InvalidValue](err)
+		assert.False(t, ok)
+	})
+}
+
+func TestHasReason(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", errs.New(InvalidValue{Name: "foo"}, errors.New("abc")))
+	assert.True(t, errs.HasReason[InvalidValue](err))
+	assert.True(t, errs.HasReason[*InvalidValue](err))
+	assert.False(t, errs.HasReason[FailToGetValue](err))
+	assert.False(t, errs.HasReason[InvalidValue](nil))
+}
+
+type reasonWithSlice struct {
+	Values []string
+}
+
+type reasonWithAny struct {
+	Value any
+}
+
+func TestReasonType(t *testing.T) {
+	assert.Equal(t, errs.ReasonType[InvalidValue](), reflect.TypeOf(InvalidValue{}))
+	assert.Equal(t, errs.ReasonType[*InvalidValue](), reflect.TypeOf(InvalidValue{}))
+	assert.Equal(t, errs.ReasonType[string](), reflect.TypeOf(""))
+}
+
+func TestReasonTypeOf(t *testing.T) {
+	assert.Equal(t, errs.ReasonTypeOf(InvalidValue{Name: "foo"}), reflect.TypeOf(InvalidValue{}))
+	assert.Equal(t, errs.ReasonTypeOf(&InvalidValue{}), reflect.TypeOf(InvalidValue{}))
+	assert.Equal(t, errs.ReasonTypeOf((*InvalidValue)(nil)), reflect.TypeOf(InvalidValue{}))
+	assert.Nil(t, errs.ReasonTypeOf(nil))
+}
+
+func TestErr_Is(t *testing.T) {
+	t.Run("zero-valued reason matches the type", func(t *testing.T) {
+		err := fmt.Errorf("wrapped: %w", errs.New(InvalidValue{Name: "foo", Value: "abc"}))
+		assert.True(t, errors.Is(err, errs.Target(InvalidValue{})))
+		assert.True(t, errors.Is(err, errs.Target(&InvalidValue{})))
+		assert.True(t, errors.Is(err, errs.Target((*InvalidValue)(nil))))
+		assert.False(t, errors.Is(err, errs.Target(FailToGetValue{})))
+	})
+
+	t.Run("non-zero reason must be equal", func(t *testing.T) {
+		err := errs.New(&InvalidValue{Name: "foo", Value: "abc"})
+		assert.True(t, errors.Is(err, errs.Target(InvalidValue{Name: "foo", Value: "abc"})))
+		assert.False(t, errors.Is(err, errs.Target(InvalidValue{Name: "foo", Value: "def"})))
+		assert.True(t, errors.Is(errs.New("abc"), errs.Target("abc")))
+		assert.False(t, errors.Is(errs.New("abc"), errs.Target("def")))
+		assert.True(t, errors.Is(errs.New("abc"), errs.Target("")))
+	})
+
+	t.Run("in the cause chain", func(t *testing.T) {
+		err := errs.New(InvalidValue{Name: "foo"}, errs.New(FailToGetValue{Name: "bar"}))
+		assert.True(t, errors.Is(err, errs.Target(FailToGetValue{Name: "bar"})))
+		assert.False(t, errors.Is(err, errs.Target(FailToGetValue{Name: "baz"})))
+	})
+
+	t.Run("a pointer to Err as the target", func(t *testing.T) {
+		target := errs.New(InvalidValue{})
+		assert.True(t, errs.New(InvalidValue{Name: "foo"}).Is(&target))
+		assert.False(t, errs.New(InvalidValue{Name: "foo"}).Is((*errs.Err)(nil)))
+	})
+
+	t.Run("ok", func(t *testing.T) {
+		assert.False(t, errs.Ok().Is(errs.Target(InvalidValue{})))
+		assert.False(t, errs.New(InvalidValue{}).Is(errs.Ok()))
+	})
+
+	t.Run("other errors", func(t *testing.T) {
+		assert.False(t, errs.New(InvalidValue{}).Is(errors.New("abc")))
+	})
+
+	t.Run("target of a reason with a slice field", func(t *testing.T) {
+		err := errs.New(reasonWithSlice{Values: []string{"a"}})
+		assert.True(t, errors.Is(err, errs.Target(reasonWithSlice{})))
+		assert.True(t, errors.Is(fmt.Errorf("wrapped: %w", err), errs.Target(reasonWithSlice{})))
+		assert.False(t, errors.Is(err, errs.Target(InvalidValue{})))
+
+		err = errs.New(errs.Panicked{Value: []int{1}})
+		assert.True(t, errors.Is(err, errs.Target(errs.Panicked{})))
+		assert.False(t, errors.Is(err, errs.Target(errs.Panicked{Value: []int{1}})))
+	})
+
+	t.Run("target as an error", func(t *testing.T) {
+		target := errs.Target(InvalidValue{Name: "foo"})
+		assert.Equal(t, target.Error(), "github.com/sttk/errs.Target {reason:github.com/sttk/errs_test.InvalidValue{Name:foo Value:}}")
+		assert.Equal(t, errs.Target(nil).Error(), "github.com/sttk/errs.Target {}")
+		assert.False(t, errors.Is(target, errs.Target(InvalidValue{})))
+		assert.True(t, errors.Is(target, target))
+		assert.False(t, errs.New(InvalidValue{}).Is(errs.Target(nil)))
+	})
+
+	t.Run("incomparable reasons", func(t *testing.T) {
+		// A non-zero target with such a reason matches nothing, even the same reason.
+		err := errs.New(reasonWithSlice{Values: []string{"a"}})
+		assert.True(t, err.Is(errs.Target(reasonWithSlice{})))
+		assert.False(t, err.Is(errs.Target(reasonWithSlice{Values: []string{"a"}})))
+		assert.False(t, err.Is(errs.Target(reasonWithSlice{Values: []string{"b"}})))
+		assert.False(t, errors.Is(err, errs.Target(reasonWithSlice{Values: []string{"a"}})))
+
+		err = errs.New(reasonWithAny{Value: []string{"a"}})
+		assert.True(t, err.Is(errs.Target(reasonWithAny{})))
+		assert.False(t, err.Is(errs.Target(reasonWithAny{Value: []string{"a"}})))
+		assert.False(t, errors.Is(err, errs.Target(reasonWithAny{Value: []string{"a"}})))
+	})
+}
+
+func TestErr_As(t *testing.T) {
+	t.Run("with errors.As", func(t *testing.T) {
+		err := fmt.Errorf("wrapped: %w", errs.New(InvalidValueError{Name: "foo", Value: "abc"}))
+
+		var r InvalidValueError
+		assert.True(t, errors.As(err, &r))
+		assert.Equal(t, r, InvalidValueError{Name: "foo", Value: "abc"})
+
+		var p *InvalidValueError
+		assert.True(t, errors.As(err, &p))
+		assert.Equal(t, p, &InvalidValueError{Name: "foo", Value: "abc"})
+	})
+
+	t.Run("with errors.As and an interface", func(t *testing.T) {
+		err := fmt.Errorf("wrapped: %w", errs.New(FailToGetValue{Name: "bar"}))
+
+		var r reasonNameGetter
+		assert.True(t, errors.As(err, &r))
+		assert.Equal(t, r.GetName(), "bar")
+	})
+
+	t.Run("pointer and value variants", func(t *testing.T) {
+		var r InvalidValue
+		assert.True(t, errs.New(&InvalidValue{Name: "foo"}).As(&r))
+		assert.Equal(t, r, InvalidValue{Name: "foo"})
+
+		var p *InvalidValue
+		assert.True(t, errs.New(InvalidValue{Name: "bar"}).As(&p))
+		assert.Equal(t, p, &InvalidValue{Name: "bar"})
+	})
+
+	t.Run("not match", func(t *testing.T) {
+		var r InvalidValue
+		assert.False(t, errs.New(FailToGetValue{}).As(&r))
+		assert.False(t, errs.Ok().As(&r))
+		assert.False(t, errs.New(InvalidValue{}).As(nil))
+		assert.False(t, errs.New(InvalidValue{}).As(r))
+		assert.False(t, errs.New(InvalidValue{}).As((*InvalidValue)(nil)))
+	})
+}
@@ -0,0 +1,129 @@
+// Copyright (C) 2025-2026 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package errs
+
+// Wrapped is the reason of an Err which wraps an error which is not an Err, such as an error
+// passed to ResultOf. The wrapped error is the cause of the Err.
+type Wrapped struct{}
+
+// Result is the struct which pairs a value with an Err, representing the result of an operation
+// which returns a value or fails.
+// If the Err indicates no error, the Result holds the value. Otherwise, the value is the zero
+// value of T.
+//
+// Since methods cannot have type parameters in Go, the combinators which change the value type
+// are provided as the functions Map and AndThen.
+type Result[T any] struct {
+	value T
+	err   Err
+}
+
+// OkResult creates a Result which holds the specified value.
+func OkResult[T any](value T) Result[T] {
+	return Result[T]{value: value}
+}
+
+// ErrResult creates a Result which holds the specified Err.
+// If the Err indicates no error, the Result holds the zero value of T.
+func ErrResult[T any](e Err) Result[T] {
+	return Result[T]{err: e}
+}
+
+// ResultOf creates a Result from a pair of a value and an error, which many functions return.
+// If the error is nil or an Err which indicates no error, the Result holds the value.
+// If the error is an Err, the Result holds it. Otherwise, the Result holds a new Err whose reason
+// is Wrapped and whose cause is the error, which records the location of the caller of this
+// function.
+func ResultOf[T any](value T, err error) Result[T] {
+	switch e := err.(type) {
+	case nil:
+		return Result[T]{value: value}
+	case Err:
+		if e.IsOk() {
+			return Result[T]{value: value}
+		}
+		return Result[T]{err: e}
+	case *Err:
+		if e == nil || e.IsOk() {
+			return Result[T]{value: value}
+		}
+		return Result[T]{err: *e}
+	default:
+		return Result[T]{err: NewSkip(1, Wrapped{}, err)}
+	}
+}
+
+// Value returns the value of this Result, which is the zero value of T if this Result holds an
+// error.
+func (r Result[T]) Value() T {
+	return r.value
+}
+
+// Err returns the Err of this Result, which indicates no error if this Result holds a value.
+func (r Result[T]) Err() Err {
+	return r.err
+}
+
+// IsOk returns true if this Result holds a value.
+func (r Result[T]) IsOk() bool {
+	return r.err.IsOk()
+}
+
+// IsNotOk returns true if this Result holds an error.
+func (r Result[T]) IsNotOk() bool {
+	return r.err.IsNotOk()
+}
+
+// Get returns the value and the error of this Result as a pair of the type (T, error).
+// The error is nil if this Result holds a value, so the pair can be checked with err != nil.
+func (r Result[T]) Get() (T, error) {
+	return r.value, r.err.AsError()
+}
+
+// Unwrap returns the value of this Result, or panics with the Err if this Result holds an error.
+func (r Result[T]) Unwrap() T {
+	if r.err.IsNotOk() {
+		panic(r.err)
+	}
+	return r.value
+}
+
+// UnwrapOr returns the value of this Result, or the specified default value if this Result holds
+// an error.
+func (r Result[T]) UnwrapOr(def T) T {
+	if r.err.IsNotOk() {
+		return def
+	}
+	return r.value
+}
+
+// OrElse returns this Result if it holds a value, or otherwise returns the Result which the
+// specified function creates from the Err. This is useful to recover from an error or to replace
+// it.
+func (r Result[T]) OrElse(fn func(Err) Result[T]) Result[T] {
+	if r.err.IsOk() {
+		return r
+	}
+	return fn(r.err)
+}
+
+// Map returns a Result which holds the value converted with the specified function if the
+// specified Result holds a value, or otherwise returns a Result which holds the same Err.
+func Map[T, U any](r Result[T], fn func(T) U) Result[U] {
+	if r.err.IsNotOk() {
+		return Result[U]{err: r.err}
+	}
+	return Result[U]{value: fn(r.value)}
+}
+
+// AndThen returns the Result which the specified function creates from the value if the
+// specified Result holds a value, or otherwise returns a Result which holds the same Err.
+// This is useful to chain operations which may fail.
+func AndThen[T, U any](r Result[T], fn func(T) Result[U]) Result[U] {
+	if r.err.IsNotOk() {
+		return Result[U]{err: r.err}
+	}
+	return fn(r.value)
+}
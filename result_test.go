@@ -0,0 +1,148 @@
+package errs_test
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/errs"
+)
+
+func TestResultOf(t *testing.T) {
+	t.Run("nil error", func(t *testing.T) {
+		r := errs.ResultOf(123, nil)
+		assert.True(t, r.IsOk())
+		assert.False(t, r.IsNotOk())
+		assert.Equal(t, r.Value(), 123)
+		assert.True(t, r.Err().IsOk())
+	})
+
+	t.Run("ok Err", func(t *testing.T) {
+		r := errs.ResultOf(123, errs.Ok())
+		assert.True(t, r.IsOk())
+		assert.Equal(t, r.Value(), 123)
+
+		e := errs.Ok()
+		r = errs.ResultOf(456, &e)
+		assert.True(t, r.IsOk())
+		assert.Equal(t, r.Value(), 456)
+	})
+
+	t.Run("Err", func(t *testing.T) {
+		e := errs.New(InvalidValue{Name: "foo"})
+		r := errs.ResultOf(123, e)
+		assert.True(t, r.IsNotOk())
+		assert.Equal(t, r.Value(), 0)
+		assert.Equal(t, r.Err(), e)
+
+		r = errs.ResultOf(123, &e)
+		assert.Equal(t, r.Err(), e)
+	})
+
+	t.Run("other error", func(t *testing.T) {
+		cause := errors.New("def")
+		r := errs.ResultOf(123, cause)
+		assert.True(t, r.IsNotOk())
+		assert.Equal(t, r.Value(), 0)
+		assert.Equal(t, r.Err().Reason(), errs.Wrapped{})
+		assert.Equal(t, r.Err().Cause(), cause)
+		assert.Equal(t, r.Err().File(), "result_test.go")
+		assert.Equal(t, r.Err().Line(), 46)
+		assert.Equal(t, fmt.Sprintf("%v", r.Err()), "github.com/sttk/errs.Wrapped: def")
+	})
+
+	t.Run("from a function", func(t *testing.T) {
+		r := errs.ResultOf(strconv.Atoi("12"))
+		assert.Equal(t, r.Value(), 12)
+
+		r = errs.ResultOf(strconv.Atoi("x"))
+		assert.True(t, r.IsNotOk())
+		assert.True(t, errors.Is(r.Err(), strconv.ErrSyntax))
+	})
+}
+
+func TestOkResultAndErrResult(t *testing.T) {
+	r := errs.OkResult("abc")
+	assert.True(t, r.IsOk())
+	assert.Equal(t, r.Value(), "abc")
+
+	e := errs.New(InvalidValue{Name: "foo"})
+	r = errs.ErrResult[string](e)
+	assert.True(t, r.IsNotOk())
+	assert.Equal(t, r.Value(), "")
+	assert.Equal(t, r.Err(), e)
+}
+
+func TestResult_Get(t *testing.T) {
+	v, err := errs.OkResult(123).Get()
+	assert.Equal(t, v, 123)
+	assert.Nil(t, err)
+
+	e := errs.New(InvalidValue{Name: "foo"})
+	v, err = errs.ErrResult[int](e).Get()
+	assert.Equal(t, v, 0)
+	assert.Equal(t, err, error(e))
+}
+
+func TestResult_Unwrap(t *testing.T) {
+	assert.Equal(t, errs.OkResult(123).Unwrap(), 123)
+
+	e := errs.New(InvalidValue{Name: "foo"})
+	assert.PanicsWithValue(t, e, func() {
+		errs.ErrResult[int](e).Unwrap()
+	})
+}
+
+func TestResult_UnwrapOr(t *testing.T) {
+	assert.Equal(t, errs.OkResult(123).UnwrapOr(456), 123)
+	assert.Equal(t, errs.ErrResult[int](errs.New("abc")).UnwrapOr(456), 456)
+}
+
+func TestResult_OrElse(t *testing.T) {
+	r := errs.OkResult(123).OrElse(func(e errs.Err) errs.Result[int] {
+		return errs.OkResult(456)
+	})
+	assert.Equal(t, r.Value(), 123)
+
+	e := errs.New(InvalidValue{Name: "foo"})
+	r = errs.ErrResult[int](e).OrElse(func(e errs.Err) errs.Result[int] {
+		if _, ok := e.Reason().(InvalidValue); ok {
+			return errs.OkResult(456)
+		}
+		return errs.ErrResult[int](e)
+	})
+	assert.Equal(t, r.Value(), 456)
+}
+
+func TestMap(t *testing.T) {
+	r := errs.Map(errs.OkResult(123), strconv.Itoa)
+	assert.Equal(t, r.Value(), "123")
+
+	e := errs.New(InvalidValue{Name: "foo"})
+	called := false
+	r = errs.Map(errs.ErrResult[int](e), func(v int) string {
+		called = true
+		return strconv.Itoa(v)
+	})
+	assert.False(t, called)
+	assert.Equal(t, r.Err(), e)
+}
+
+func TestAndThen(t *testing.T) {
+	parse := func(s string) errs.Result[int] {
+		return errs.ResultOf(strconv.Atoi(s))
+	}
+
+	r := errs.AndThen(errs.OkResult("12"), parse)
+	assert.Equal(t, r.Value(), 12)
+
+	r = errs.AndThen(errs.OkResult("x"), parse)
+	assert.True(t, r.IsNotOk())
+	assert.Equal(t, r.Err().Reason(), errs.Wrapped{})
+
+	e := errs.New(InvalidValue{Name: "foo"})
+	r = errs.AndThen(errs.ErrResult[string](e), parse)
+	assert.Equal(t, r.Err(), e)
+}
@@ -0,0 +1,87 @@
+// Copyright (C) 2026 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package retry
+
+import (
+	"math"
+	"time"
+)
+
+// Policy is the struct which configures how Do retries an operation.
+//
+// The interval before the n-th retry is InitialInterval multiplied by Multiplier n-1 times, and
+// is capped by MaxInterval. Then the interval is randomized by Jitter.
+type Policy struct {
+	// MaxAttempts is the maximum number of attempts, including the first one.
+	// If this is zero or negative, the number of attempts is not limited, and the retries continue
+	// until the operation succeeds, fails with a non-retryable error, MaxElapsedTime passes, or
+	// the context is done. In this case, only the errs.Err values of the last MaxRetainedCauses
+	// attempts are retained as the causes of the errs.Err which Do returns.
+	MaxAttempts int
+
+	// InitialInterval is the interval before the first retry.
+	// If this is zero or negative, DefaultInitialInterval is used, so that the zero Policy does not
+	// retry without waiting.
+	InitialInterval time.Duration
+
+	// MaxInterval is the upper limit of the interval between attempts.
+	// If this is zero or negative, the interval is not limited.
+	MaxInterval time.Duration
+
+	// Multiplier is the factor by which the interval is multiplied for each retry.
+	// If this is less than 1, the interval is constant.
+	Multiplier float64
+
+	// Jitter is the ratio of the random variation of the interval, which is between 0 and 1.
+	// For example, if this is 0.2, the interval is randomized within plus or minus 20%.
+	Jitter float64
+
+	// MaxElapsedTime is the upper limit of the time from the start of the first attempt.
+	// No retry is started if the interval before it would end after this time.
+	// If this is zero or negative, the time is not limited.
+	MaxElapsedTime time.Duration
+}
+
+// DefaultInitialInterval is the interval before the first retry, which is used when the
+// InitialInterval of a Policy is zero or negative.
+const DefaultInitialInterval = 100 * time.Millisecond
+
+// DefaultPolicy returns a Policy which attempts at most 3 times with exponential backoff, starting
+// from 100 milliseconds with the multiplier 2, the max interval 10 seconds, and the jitter 0.2.
+func DefaultPolicy() Policy {
+	return Policy{
+		MaxAttempts:     3,
+		InitialInterval: DefaultInitialInterval,
+		MaxInterval:     10 * time.Second,
+		Multiplier:      2,
+		Jitter:          0.2,
+	}
+}
+
+// interval returns the interval before the retry following the specified number of attempts.
+// The argument rnd is a random number in [0, 1), which is used for the jitter.
+func (p Policy) interval(attempts int, rnd float64) time.Duration {
+	d := float64(p.InitialInterval)
+	if d <= 0 {
+		d = float64(DefaultInitialInterval)
+	}
+	if p.Multiplier > 1 {
+		d *= math.Pow(p.Multiplier, float64(attempts-1))
+	}
+	if p.MaxInterval > 0 && d > float64(p.MaxInterval) {
+		d = float64(p.MaxInterval)
+	}
+	if p.Jitter > 0 {
+		jitter := math.Min(p.Jitter, 1)
+		d *= 1 + jitter*(2*rnd-1)
+	}
+	if d <= 0 {
+		return 0
+	}
+	if d >= math.MaxInt64 {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(d)
+}
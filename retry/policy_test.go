@@ -0,0 +1,68 @@
+package retry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultPolicy(t *testing.T) {
+	p := DefaultPolicy()
+	assert.Equal(t, p.MaxAttempts, 3)
+	assert.Equal(t, p.InitialInterval, 100*time.Millisecond)
+	assert.Equal(t, p.MaxInterval, 10*time.Second)
+	assert.Equal(t, p.Multiplier, 2.0)
+	assert.Equal(t, p.Jitter, 0.2)
+	assert.Equal(t, p.MaxElapsedTime, time.Duration(0))
+}
+
+func TestPolicy_interval(t *testing.T) {
+	t.Run("exponential", func(t *testing.T) {
+		p := Policy{InitialInterval: time.Second, Multiplier: 2}
+		assert.Equal(t, p.interval(1, 0.5), time.Second)
+		assert.Equal(t, p.interval(2, 0.5), 2*time.Second)
+		assert.Equal(t, p.interval(3, 0.5), 4*time.Second)
+	})
+
+	t.Run("constant", func(t *testing.T) {
+		p := Policy{InitialInterval: time.Second}
+		assert.Equal(t, p.interval(1, 0.5), time.Second)
+		assert.Equal(t, p.interval(5, 0.5), time.Second)
+
+		p.Multiplier = 0.5
+		assert.Equal(t, p.interval(5, 0.5), time.Second)
+	})
+
+	t.Run("max interval", func(t *testing.T) {
+		p := Policy{InitialInterval: time.Second, Multiplier: 2, MaxInterval: 3 * time.Second}
+		assert.Equal(t, p.interval(2, 0.5), 2*time.Second)
+		assert.Equal(t, p.interval(3, 0.5), 3*time.Second)
+		assert.Equal(t, p.interval(100, 0.5), 3*time.Second)
+	})
+
+	t.Run("jitter", func(t *testing.T) {
+		p := Policy{InitialInterval: time.Second, Jitter: 0.2}
+		assert.Equal(t, p.interval(1, 0), 800*time.Millisecond)
+		assert.Equal(t, p.interval(1, 0.5), time.Second)
+		assert.Equal(t, p.interval(1, 0.75), 1100*time.Millisecond)
+
+		p.Jitter = 2
+		assert.Equal(t, p.interval(1, 0), time.Duration(0))
+		assert.Equal(t, p.interval(1, 0.75), 1500*time.Millisecond)
+	})
+
+	t.Run("zero initial interval", func(t *testing.T) {
+		p := Policy{Multiplier: 2}
+		assert.Equal(t, p.interval(1, 0.5), DefaultInitialInterval)
+		assert.Equal(t, p.interval(2, 0.5), 2*DefaultInitialInterval)
+
+		p = Policy{InitialInterval: -time.Second}
+		assert.Equal(t, p.interval(1, 0.5), DefaultInitialInterval)
+	})
+
+	t.Run("overflow", func(t *testing.T) {
+		p := Policy{InitialInterval: time.Second, Multiplier: 10}
+		assert.Equal(t, p.interval(100, 0.5), time.Duration(1<<63-1))
+	})
+}
@@ -0,0 +1,135 @@
+// Copyright (C) 2026 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+// Package retry is for retrying operations which fail with errs.Err values, deciding whether
+// to retry by the reasons of the errs.Err values.
+//
+// # Retryable reasons
+//
+// Whether an operation can be retried is determined by the reason of the errs.Err with which it
+// failed. If the reason has the method Retryable() bool, the result of the method is used.
+// Otherwise, the operation can be retried only if the reason type is registered with
+// RegisterRetryable.
+//
+//	type Timeout struct{}
+//
+//	func (r Timeout) Retryable() bool { return true }
+//
+//	func init() {
+//	    retry.RegisterRetryable[ServiceUnavailable]()
+//	}
+//
+// # Retrying
+//
+// Do calls an operation repeatedly while it fails with a retryable errs.Err, waiting for
+// intervals of exponential backoff with jitter configured by a Policy.
+// If the operation does not succeed, Do returns an errs.Err whose reason is Exhausted,
+// NotRetryable or Interrupted, and whose causes are the errs.Err values of all the attempts.
+//
+//	e := retry.Do(ctx, retry.DefaultPolicy(), func() errs.Err {
+//	    return callService()
+//	})
+//	if e.IsNotOk() {
+//	    return e
+//	}
+package retry
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/sttk/errs"
+)
+
+type /* error reasons */ (
+	// Exhausted is the reason of an errs.Err which Do returns when the operation still fails with a
+	// retryable errs.Err after the max attempts or the max elapsed time of the policy.
+	Exhausted struct {
+		Attempts int
+	}
+
+	// NotRetryable is the reason of an errs.Err which Do returns when the operation fails with an
+	// errs.Err which is not retryable.
+	NotRetryable struct {
+		Attempts int
+	}
+
+	// Interrupted is the reason of an errs.Err which Do returns when the context is done before the
+	// operation succeeds. The last cause of the errs.Err is the error of the context.
+	Interrupted struct {
+		Attempts int
+	}
+)
+
+func init() {
+	errs.RegisterReason[Exhausted]()
+	errs.RegisterReason[NotRetryable]()
+	errs.RegisterReason[Interrupted]()
+}
+
+// MaxRetainedCauses is the maximum number of the errs.Err values of the attempts which Do retains
+// as the causes when the MaxAttempts of the policy is zero or negative.
+const MaxRetainedCauses = 100
+
+// Do calls the specified function, and retries it according to the policy while it fails with a
+// retryable errs.Err. (See IsRetryable.)
+// If the function succeeds, this function returns errs.Ok.
+// Otherwise, this function returns an errs.Err whose reason is Exhausted, NotRetryable or
+// Interrupted, and whose causes are the errs.Err values of all the attempts in order. (If the
+// number of attempts is not limited, only the last MaxRetainedCauses of them are retained.)
+// The location of the returned errs.Err is the caller of this function.
+//
+// This function checks the context before each attempt and while waiting for an interval, but
+// does not interrupt a running attempt. To interrupt it, use the context in the function.
+func Do(ctx context.Context, policy Policy, fn func() errs.Err) errs.Err {
+	start := time.Now()
+	var causes []error
+
+	for attempts := 0; ; {
+		if err := ctx.Err(); err != nil {
+			return errs.NewSkip(1, Interrupted{Attempts: attempts}, append(causes, err)...)
+		}
+
+		e := fn()
+		attempts++
+		if e.IsOk() {
+			return errs.Ok()
+		}
+		if policy.MaxAttempts <= 0 && len(causes) >= MaxRetainedCauses {
+			causes = append(causes[:0], causes[len(causes)-MaxRetainedCauses+1:]...)
+		}
+		causes = append(causes, e)
+
+		if !IsRetryable(e) {
+			return errs.NewSkip(1, NotRetryable{Attempts: attempts}, causes...)
+		}
+		if policy.MaxAttempts > 0 && attempts >= policy.MaxAttempts {
+			return errs.NewSkip(1, Exhausted{Attempts: attempts}, causes...)
+		}
+
+		d := policy.interval(attempts, rand.Float64())
+		if policy.MaxElapsedTime > 0 && time.Since(start)+d > policy.MaxElapsedTime {
+			return errs.NewSkip(1, Exhausted{Attempts: attempts}, causes...)
+		}
+
+		if err := wait(ctx, d); err != nil {
+			return errs.NewSkip(1, Interrupted{Attempts: attempts}, append(causes, err)...)
+		}
+	}
+}
+
+func wait(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
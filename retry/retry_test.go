@@ -0,0 +1,140 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/errs"
+	"github.com/sttk/errs/retry"
+)
+
+var testPolicy = retry.Policy{
+	MaxAttempts:     3,
+	InitialInterval: time.Millisecond,
+	Multiplier:      2,
+}
+
+func TestDo(t *testing.T) {
+	t.Run("succeed at first", func(t *testing.T) {
+		var count int
+		e := retry.Do(context.Background(), testPolicy, func() errs.Err {
+			count++
+			return errs.Ok()
+		})
+		assert.True(t, e.IsOk())
+		assert.Equal(t, count, 1)
+	})
+
+	t.Run("succeed after retries", func(t *testing.T) {
+		var count int
+		e := retry.Do(context.Background(), testPolicy, func() errs.Err {
+			count++
+			if count < 3 {
+				return errs.New(Timeout{})
+			}
+			return errs.Ok()
+		})
+		assert.True(t, e.IsOk())
+		assert.Equal(t, count, 3)
+	})
+
+	t.Run("exhausted", func(t *testing.T) {
+		var count int
+		e := retry.Do(context.Background(), testPolicy, func() errs.Err {
+			count++
+			return errs.New(Timeout{})
+		})
+		assert.Equal(t, count, 3)
+		assert.Equal(t, e.Reason(), retry.Exhausted{Attempts: 3})
+		assert.Len(t, e.Causes(), 3)
+		for _, c := range e.Causes() {
+			assert.Equal(t, c.(errs.Err).Reason(), Timeout{})
+		}
+		assert.True(t, errs.HasReason[Timeout](e))
+		assert.Equal(t, e.File(), "retry_test.go")
+		assert.Equal(t, e.Line(), 46)
+	})
+
+	t.Run("not retryable", func(t *testing.T) {
+		var count int
+		e := retry.Do(context.Background(), testPolicy, func() errs.Err {
+			count++
+			if count == 1 {
+				return errs.New(Overloaded{})
+			}
+			return errs.New(InvalidValue{Name: "abc"})
+		})
+		assert.Equal(t, count, 2)
+		assert.Equal(t, e.Reason(), retry.NotRetryable{Attempts: 2})
+		assert.Len(t, e.Causes(), 2)
+		assert.Equal(t, e.Causes()[0].(errs.Err).Reason(), Overloaded{})
+		assert.Equal(t, e.Causes()[1].(errs.Err).Reason(), InvalidValue{Name: "abc"})
+	})
+
+	t.Run("max elapsed time", func(t *testing.T) {
+		policy := retry.Policy{
+			InitialInterval: 100 * time.Millisecond,
+			MaxElapsedTime:  250 * time.Millisecond,
+		}
+
+		var count int
+		e := retry.Do(context.Background(), policy, func() errs.Err {
+			count++
+			return errs.New(Timeout{})
+		})
+		assert.Equal(t, count, 3)
+		assert.Equal(t, e.Reason(), retry.Exhausted{Attempts: 3})
+	})
+
+	t.Run("context done before the first attempt", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		var count int
+		e := retry.Do(ctx, testPolicy, func() errs.Err {
+			count++
+			return errs.Ok()
+		})
+		assert.Equal(t, count, 0)
+		assert.Equal(t, e.Reason(), retry.Interrupted{Attempts: 0})
+		assert.Equal(t, e.Causes(), []error{context.Canceled})
+	})
+
+	t.Run("context done while waiting", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		policy := retry.Policy{InitialInterval: time.Hour}
+
+		var count int
+		e := retry.Do(ctx, policy, func() errs.Err {
+			count++
+			return errs.New(Timeout{})
+		})
+		assert.Equal(t, count, 1)
+		assert.Equal(t, e.Reason(), retry.Interrupted{Attempts: 1})
+		assert.Len(t, e.Causes(), 2)
+		assert.Equal(t, e.Causes()[0].(errs.Err).Reason(), Timeout{})
+		assert.True(t, errors.Is(e, context.DeadlineExceeded))
+	})
+	t.Run("causes are capped when attempts are not limited", func(t *testing.T) {
+		policy := retry.Policy{InitialInterval: time.Nanosecond}
+
+		var results []errs.Err
+		e := retry.Do(context.Background(), policy, func() errs.Err {
+			if len(results) < retry.MaxRetainedCauses+50 {
+				results = append(results, errs.New(Timeout{}))
+			} else {
+				results = append(results, errs.New(InvalidValue{Name: "abc"}))
+			}
+			return results[len(results)-1]
+		})
+		assert.Equal(t, e.Reason(), retry.NotRetryable{Attempts: retry.MaxRetainedCauses + 51})
+		assert.Len(t, e.Causes(), retry.MaxRetainedCauses)
+		assert.Equal(t, e.Causes()[0], error(results[51]))
+		assert.Equal(t, e.Causes()[retry.MaxRetainedCauses-1], error(results[retry.MaxRetainedCauses+50]))
+	})
+}
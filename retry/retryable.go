@@ -0,0 +1,66 @@
+// Copyright (C) 2026 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package retry
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/sttk/errs"
+)
+
+// RetryableReason is the interface which a reason can implement to specify whether the operation
+// which failed with an errs.Err with the reason can be retried.
+type RetryableReason interface {
+	Retryable() bool
+}
+
+var (
+	retryableRegistry = map[reflect.Type]struct{}{}
+	retryableMutex    sync.RWMutex
+)
+
+// RegisterRetryable registers the reason type T as retryable.
+// An errs.Err whose reason is a T and one whose reason is a *T are both retryable after either of
+// T and *T is registered.
+// If the reason implements RetryableReason, the result of its Retryable method takes precedence
+// over the registration.
+//
+// Since Do consults the registrations on every failure, register the reason types before starting
+// operations to be retried, for example in an init function.
+func RegisterRetryable[T any]() {
+	t := errs.ReasonType[T]()
+
+	retryableMutex.Lock()
+	defer retryableMutex.Unlock()
+	retryableRegistry[t] = struct{}{}
+}
+
+// IsRetryable reports whether the operation which failed with the specified errs.Err can be
+// retried.
+// If the reason implements RetryableReason, the result of its Retryable method is returned.
+// Otherwise, this function returns true if the reason type is registered with RegisterRetryable.
+// If the errs.Err indicates no error, this function returns false.
+func IsRetryable(e errs.Err) bool {
+	reason := e.Reason()
+	if reason == nil {
+		return false
+	}
+
+	if r, ok := reason.(RetryableReason); ok {
+		return r.Retryable()
+	}
+	v := reflect.ValueOf(reason)
+	if v.Kind() == reflect.Ptr && !v.IsNil() {
+		if r, ok := v.Elem().Interface().(RetryableReason); ok {
+			return r.Retryable()
+		}
+	}
+
+	retryableMutex.RLock()
+	defer retryableMutex.RUnlock()
+	_, ok := retryableRegistry[errs.ReasonTypeOf(reason)]
+	return ok
+}
@@ -0,0 +1,62 @@
+package retry_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/errs"
+	"github.com/sttk/errs/retry"
+)
+
+type /* error reasons */ (
+	Timeout struct{}
+
+	Unavailable struct {
+		Transient bool
+	}
+
+	Overloaded struct{}
+
+	InvalidValue struct {
+		Name string
+	}
+)
+
+func (r Timeout) Retryable() bool {
+	return true
+}
+
+func (r *Unavailable) Retryable() bool {
+	return r.Transient
+}
+
+func init() {
+	retry.RegisterRetryable[*Overloaded]()
+}
+
+func TestIsRetryable(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		assert.False(t, retry.IsRetryable(errs.Ok()))
+	})
+
+	t.Run("reason with Retryable method", func(t *testing.T) {
+		assert.True(t, retry.IsRetryable(errs.New(Timeout{})))
+		assert.True(t, retry.IsRetryable(errs.New(&Timeout{})))
+	})
+
+	t.Run("reason with Retryable method of pointer receiver", func(t *testing.T) {
+		assert.True(t, retry.IsRetryable(errs.New(&Unavailable{Transient: true})))
+		assert.False(t, retry.IsRetryable(errs.New(&Unavailable{Transient: false})))
+		assert.False(t, retry.IsRetryable(errs.New(Unavailable{Transient: true})))
+	})
+
+	t.Run("registered reason", func(t *testing.T) {
+		assert.True(t, retry.IsRetryable(errs.New(Overloaded{})))
+		assert.True(t, retry.IsRetryable(errs.New(&Overloaded{})))
+	})
+
+	t.Run("other reason", func(t *testing.T) {
+		assert.False(t, retry.IsRetryable(errs.New(InvalidValue{Name: "abc"})))
+		assert.False(t, retry.IsRetryable(errs.New("abc")))
+	})
+}
@@ -0,0 +1,207 @@
+//go:build go1.21
+
+// Copyright (C) 2025-2026 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package errs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strconv"
+)
+
+// LogValue implements slog.LogValuer, and returns a group value which consists of the following
+// attributes:
+//
+//	reason_type   the fully qualified type name of the reason.
+//	reason        a group of the reason's fields if the reason is a struct, otherwise the reason
+//	              itself.
+//	file          the file name where this Err was created.
+//	line          the line number where this Err was created.
+//	cause         a nested group of the same form if the cause is an Err, otherwise the message
+//	              of the cause. (This is omitted when there is no cause.)
+//	causes        a group of the causes keyed by their indexes, each in the same form as cause.
+//	              (This is used instead of cause when there are multiple causes.)
+//
+// An Err which indicates no error is logged as the string "ok".
+//
+// NOTE: This method is available on Go 1.21 or later.
+func (e Err) LogValue() slog.Value {
+	if e.reason == nil { // Ok
+		return slog.StringValue("ok")
+	}
+
+	attrs := make([]slog.Attr, 0, 5)
+
+	if r, ok := e.reason.(UnknownReason); ok {
+		attrs = append(attrs, slog.String("reason_type", r.Type), slog.String("reason", r.Value))
+	} else {
+		attrs = append(attrs, slog.String("reason_type", reasonTypeName(reflect.TypeOf(e.reason))))
+		attrs = append(attrs, reasonAttr(e.reason))
+	}
+
+	attrs = append(attrs, slog.String("file", e.file), slog.Int("line", e.line))
+
+	if e.causes != nil {
+		causeAttrs := make([]slog.Attr, len(*e.causes))
+		for i, c := range *e.causes {
+			causeAttrs[i] = causeAttr(strconv.Itoa(i), c)
+		}
+		attrs = append(attrs, slog.Attr{Key: "causes", Value: slog.GroupValue(causeAttrs...)})
+	} else if e.cause != nil {
+		attrs = append(attrs, causeAttr("cause", e.cause))
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+func causeAttr(key string, cause error) slog.Attr {
+	if c, ok := cause.(Err); ok && c.IsNotOk() {
+		return slog.Attr{Key: key, Value: c.LogValue()}
+	}
+	return slog.String(key, cause.Error())
+}
+
+func reasonAttr(reason any) slog.Attr {
+	v := reflect.ValueOf(reason)
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return slog.Any("reason", nil)
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		if v.CanInterface() {
+			return slog.Any("reason", v.Interface())
+		}
+		return slog.String("reason", fmt.Sprintf("%v", v))
+	}
+
+	t := v.Type()
+	flds := make([]slog.Attr, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := v.Field(i)
+		if f.CanInterface() {
+			flds = append(flds, slog.Any(t.Field(i).Name, f.Interface()))
+		} else {
+			flds = append(flds, slog.String(t.Field(i).Name, fmt.Sprintf("%+v", f)))
+		}
+	}
+	return slog.Attr{Key: "reason", Value: slog.GroupValue(flds...)}
+}
+
+// SlogHandler is a slog.Handler which decorates another slog.Handler.
+// This handler expands every Err found in the attributes of log records, including Err(s) wrapped
+// in other errors, into groups with the form of Err.LogValue.
+// An error which wraps an Err is expanded into a group which has the attribute "message" holding
+// the error's message, followed by the attributes of the wrapped Err.
+//
+// NOTE: This struct is available on Go 1.21 or later.
+type SlogHandler struct {
+	handler slog.Handler
+}
+
+// NewSlogHandler creates a new SlogHandler which decorates the specified slog.Handler.
+//
+// NOTE: This function is available on Go 1.21 or later.
+func NewSlogHandler(h slog.Handler) *SlogHandler {
+	return &SlogHandler{handler: h}
+}
+
+// Enabled reports whether the decorated handler handles records at the given level.
+func (h *SlogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+// Handle expands Err(s) in the attributes of the record and passes the record to the decorated
+// handler.
+func (h *SlogHandler) Handle(ctx context.Context, r slog.Record) error {
+	r2 := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		r2.AddAttrs(expandErrAttr(a))
+		return true
+	})
+	return h.handler.Handle(ctx, r2)
+}
+
+// WithAttrs returns a new SlogHandler whose decorated handler has the specified attributes, in
+// which Err(s) are expanded.
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	expanded := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		expanded[i] = expandErrAttr(a)
+	}
+	return &SlogHandler{handler: h.handler.WithAttrs(expanded)}
+}
+
+// WithGroup returns a new SlogHandler whose decorated handler has the specified group.
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	return &SlogHandler{handler: h.handler.WithGroup(name)}
+}
+
+func expandErrAttr(a slog.Attr) slog.Attr {
+	a.Value = a.Value.Resolve()
+
+	switch a.Value.Kind() {
+	case slog.KindGroup:
+		grp := a.Value.Group()
+		expanded := make([]slog.Attr, len(grp))
+		for i, ga := range grp {
+			expanded[i] = expandErrAttr(ga)
+		}
+		a.Value = slog.GroupValue(expanded...)
+
+	case slog.KindAny:
+		err, ok := a.Value.Any().(error)
+		if !ok || err == nil {
+			break
+		}
+		var e Err
+		if !errors.As(err, &e) || e.IsOk() {
+			break
+		}
+		v := e.LogValue()
+		attrs := make([]slog.Attr, 0, len(v.Group())+1)
+		attrs = append(attrs, slog.String("message", err.Error()))
+		attrs = append(attrs, v.Group()...)
+		a.Value = slog.GroupValue(attrs...)
+	}
+
+	return a
+}
+
+// LogValue implements slog.LogValuer, and returns a group value which consists of the following
+// attributes:
+//
+//	total           the number of all the items.
+//	failed          the number of the failed items.
+//	success_ratio   the ratio of the succeeded items to all the items.
+//	items           a group of the failed items keyed by their indexes or IDs, each of which is
+//	                in the form of Err.LogValue. (This is omitted when there is no failed item.)
+//
+// NOTE: This method is available on Go 1.21 or later.
+func (b *BatchErr) LogValue() slog.Value {
+	items := b.Items()
+
+	attrs := make([]slog.Attr, 0, 4)
+	attrs = append(attrs,
+		slog.Int("total", b.Total()),
+		slog.Int("failed", len(items)),
+		slog.Float64("success_ratio", b.SuccessRatio()),
+	)
+
+	if len(items) > 0 {
+		itemAttrs := make([]slog.Attr, len(items))
+		for i, it := range items {
+			itemAttrs[i] = slog.Attr{Key: it.Key(), Value: it.Err.LogValue()}
+		}
+		attrs = append(attrs, slog.Attr{Key: "items", Value: slog.GroupValue(itemAttrs...)})
+	}
+
+	return slog.GroupValue(attrs...)
+}
@@ -0,0 +1,126 @@
+//go:build go1.21
+
+package errs_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/errs"
+)
+
+func newTestJSONHandler(buf *bytes.Buffer) slog.Handler {
+	return slog.NewJSONHandler(buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+}
+
+func TestErr_LogValue(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(newTestJSONHandler(&buf))
+
+		logger.Info("msg", "err", errs.Ok())
+		assert.Equal(t, strings.TrimSpace(buf.String()), `{"level":"INFO","msg":"msg","err":"ok"}`)
+	})
+
+	t.Run("reason is a struct", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(newTestJSONHandler(&buf))
+
+		err := errs.New(&InvalidValue{Name: "foo", Value: "abc"})
+		logger.Info("msg", "err", err)
+		assert.Equal(t, strings.TrimSpace(buf.String()), `{"level":"INFO","msg":"msg","err":{"reason_type":"*github.com/sttk/errs_test.InvalidValue","reason":{"Name":"foo","Value":"abc"},"file":"slog_test.go","line":42}}`)
+	})
+
+	t.Run("reason is not a struct", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(newTestJSONHandler(&buf))
+
+		err := errs.New(123)
+		logger.Info("msg", "err", err)
+		assert.Equal(t, strings.TrimSpace(buf.String()), `{"level":"INFO","msg":"msg","err":{"reason_type":"int","reason":123,"file":"slog_test.go","line":51}}`)
+	})
+
+	t.Run("with cause chain", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(newTestJSONHandler(&buf))
+
+		cause := errs.New(FailToGetValue{Name: "foo"}, errors.New("def"))
+		err := errs.New(InvalidValue{Name: "foo", Value: "abc"}, cause)
+		logger.Info("msg", "err", err)
+		assert.Equal(t, strings.TrimSpace(buf.String()), `{"level":"INFO","msg":"msg","err":{"reason_type":"github.com/sttk/errs_test.InvalidValue","reason":{"Name":"foo","Value":"abc"},"file":"slog_test.go","line":61,"cause":{"reason_type":"github.com/sttk/errs_test.FailToGetValue","reason":{"Name":"foo"},"file":"slog_test.go","line":60,"cause":"def"}}}`)
+	})
+}
+
+func TestSlogHandler(t *testing.T) {
+	t.Run("expand an Err wrapped in another error", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(errs.NewSlogHandler(newTestJSONHandler(&buf)))
+
+		err := fmt.Errorf("wrapped: %w", errs.New(FailToGetValue{Name: "foo"}))
+		logger.Info("msg", "err", err)
+		assert.Equal(t, strings.TrimSpace(buf.String()), `{"level":"INFO","msg":"msg","err":{"message":"wrapped: github.com/sttk/errs_test.FailToGetValue{Name:foo}","reason_type":"github.com/sttk/errs_test.FailToGetValue","reason":{"Name":"foo"},"file":"slog_test.go","line":72}}`)
+	})
+
+	t.Run("expand Err(s) in groups and handler attributes", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(errs.NewSlogHandler(newTestJSONHandler(&buf)))
+
+		err := fmt.Errorf("wrapped: %w", errs.New("abc"))
+		logger = logger.With("base", err).WithGroup("g")
+		logger.Info("msg", slog.Group("sub", "err", err, "n", 1))
+		assert.Equal(t, strings.TrimSpace(buf.String()), `{"level":"INFO","msg":"msg","base":{"message":"wrapped: abc","reason_type":"string","reason":"abc","file":"slog_test.go","line":81},"g":{"sub":{"err":{"message":"wrapped: abc","reason_type":"string","reason":"abc","file":"slog_test.go","line":81},"n":1}}}`)
+	})
+
+	t.Run("not expand other errors and ok", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(errs.NewSlogHandler(newTestJSONHandler(&buf)))
+
+		logger.Info("msg", "err1", errors.New("def"), "err2", fmt.Errorf("x: %w", errs.Ok()))
+		assert.Equal(t, strings.TrimSpace(buf.String()), `{"level":"INFO","msg":"msg","err1":"def","err2":"x: ok"}`)
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		var buf bytes.Buffer
+		h := errs.NewSlogHandler(newTestJSONHandler(&buf))
+		assert.True(t, h.Enabled(context.Background(), slog.LevelInfo))
+		assert.False(t, h.Enabled(context.Background(), slog.LevelDebug))
+	})
+}
+
+func TestErr_LogValue_multipleCauses(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(newTestJSONHandler(&buf))
+
+	cause := errs.New(FailToGetValue{Name: "foo"})
+	err := errs.New("abc", errors.New("def"), cause)
+	logger.Info("msg", "err", err)
+	assert.Equal(t, strings.TrimSpace(buf.String()), `{"level":"INFO","msg":"msg","err":{"reason_type":"string","reason":"abc","file":"slog_test.go","line":108,"causes":{"0":"def","1":{"reason_type":"github.com/sttk/errs_test.FailToGetValue","reason":{"Name":"foo"},"file":"slog_test.go","line":107}}}}`)
+}
+
+func TestBatchErr_LogValue(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(newTestJSONHandler(&buf))
+
+	b := errs.NewBatchErr(4).
+		Add(1, errs.New(FailToGetValue{Name: "foo"})).
+		AddID("x", errs.New("abc"))
+	logger.Info("msg", "err", b)
+	assert.Equal(t, strings.TrimSpace(buf.String()), `{"level":"INFO","msg":"msg","err":{"total":4,"failed":2,"success_ratio":0.5,"items":{"1":{"reason_type":"github.com/sttk/errs_test.FailToGetValue","reason":{"Name":"foo"},"file":"slog_test.go","line":118},"x":{"reason_type":"string","reason":"abc","file":"slog_test.go","line":119}}}}`)
+
+	buf.Reset()
+	logger.Info("msg", "err", errs.NewBatchErr(4))
+	assert.Equal(t, strings.TrimSpace(buf.String()), `{"level":"INFO","msg":"msg","err":{"total":4,"failed":0,"success_ratio":1}}`)
+}
@@ -0,0 +1,137 @@
+// Copyright (C) 2025-2026 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package errs
+
+import (
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync/atomic"
+)
+
+// Frame is the struct that represents a symbolized frame of a stack trace.
+type Frame struct {
+	// Function is the package path-qualified function name of this frame.
+	Function string
+
+	// File is the full path of the source file of this frame.
+	File string
+
+	// Line is the line number in the source file of this frame.
+	Line int
+}
+
+// callStack holds program counters captured at the creation of an Err.
+// This is held via a pointer in Err so that Err remains comparable.
+type callStack struct {
+	pcs []uintptr
+}
+
+var stackTraceDepth int32 = 0
+
+// MaxStackTraceDepth is the upper limit of the depth which can be set with SetStackTraceDepth.
+const MaxStackTraceDepth = 1024
+
+// SetStackTraceDepth sets the maximum number of stack frames captured when an Err is created.
+// If the depth is zero or negative, stack traces are not captured. This is the default, and in
+// this case only File and Line are recorded, keeping the cost of New as before.
+// If the depth is greater than MaxStackTraceDepth, MaxStackTraceDepth is set instead.
+//
+// This setting can be changed at any time and affects Err(s) created after the change.
+func SetStackTraceDepth(depth int) {
+	if depth < 0 {
+		depth = 0
+	} else if depth > MaxStackTraceDepth {
+		depth = MaxStackTraceDepth
+	}
+	atomic.StoreInt32(&stackTraceDepth, int32(depth))
+}
+
+// StackTraceDepth returns the current maximum number of stack frames captured when an Err is
+// created. Zero means stack traces are not captured.
+func StackTraceDepth() int {
+	return int(atomic.LoadInt32(&stackTraceDepth))
+}
+
+// captureStack captures program counters of the current goroutine's stack.
+// The argument skip is the number of stack frames to skip, with 0 identifying the caller of
+// captureStack.
+func captureStack(skip int) *callStack {
+	depth := atomic.LoadInt32(&stackTraceDepth)
+	if depth <= 0 {
+		return nil
+	}
+	pcs := make([]uintptr, depth)
+	n := runtime.Callers(skip+2, pcs)
+	if n == 0 {
+		return nil
+	}
+	return &callStack{pcs: pcs[:n:n]}
+}
+
+func (s *callStack) frames() []Frame {
+	if s == nil {
+		return nil
+	}
+	frames := make([]Frame, 0, len(s.pcs))
+	iter := runtime.CallersFrames(s.pcs)
+	for {
+		f, more := iter.Next()
+		frames = append(frames, Frame{Function: f.Function, File: f.File, Line: f.Line})
+		if !more {
+			break
+		}
+	}
+	return frames
+}
+
+// maxPanicFrames is the maximum number of stack frames searched for the site of a panic.
+const maxPanicFrames = 64
+
+// capturePanicSite finds the site of the current panic from a deferred function, and returns its
+// file, line, and the stack trace starting from it.
+// The argument skip is the number of stack frames to skip, with 0 identifying the caller of
+// capturePanicSite.
+// The frames of the deferred functions and the runtime functions handling the panic are trimmed,
+// so the panic site is the first frame after the runtime frames. If no runtime frame is found,
+// the caller identified by skip is regarded as the site.
+func capturePanicSite(skip int) (file string, line int, stack *callStack) {
+	depth := int(atomic.LoadInt32(&stackTraceDepth))
+	pcs := make([]uintptr, maxPanicFrames+depth)
+	n := runtime.Callers(skip+2, pcs)
+	pcs = pcs[:n]
+
+	site := 0
+	for i, pc := range pcs {
+		if !isRuntimeFrame(pc) {
+			continue
+		}
+		for site = i + 1; site < len(pcs) && isRuntimeFrame(pcs[site]); site++ {
+		}
+		break
+	}
+	if site >= len(pcs) {
+		site = 0
+	}
+	pcs = pcs[site:]
+
+	if len(pcs) > 0 {
+		f, _ := runtime.CallersFrames(pcs).Next()
+		file = filepath.Base(f.File)
+		line = f.Line
+	}
+	if depth > 0 && len(pcs) > 0 {
+		if len(pcs) > depth {
+			pcs = pcs[:depth]
+		}
+		stack = &callStack{pcs: pcs[:len(pcs):len(pcs)]}
+	}
+	return
+}
+
+func isRuntimeFrame(pc uintptr) bool {
+	fn := runtime.FuncForPC(pc - 1)
+	return fn != nil && strings.HasPrefix(fn.Name(), "runtime.")
+}
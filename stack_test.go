@@ -0,0 +1,81 @@
+package errs_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/errs"
+)
+
+func newErrForStackTest() errs.Err {
+	return errs.New(FailToGetValue{Name: "foo"})
+}
+
+func TestStackTrace(t *testing.T) {
+	t.Run("not captured by default", func(t *testing.T) {
+		assert.Equal(t, errs.StackTraceDepth(), 0)
+
+		err := errs.New(FailToGetValue{Name: "foo"})
+		assert.Nil(t, err.StackTrace())
+		assert.Equal(t, err.File(), "stack_test.go")
+		assert.Equal(t, err.Line(), 20)
+	})
+
+	t.Run("captured when depth is set", func(t *testing.T) {
+		errs.SetStackTraceDepth(32)
+		defer errs.SetStackTraceDepth(0)
+		assert.Equal(t, errs.StackTraceDepth(), 32)
+
+		err := newErrForStackTest()
+		frames := err.StackTrace()
+		assert.True(t, len(frames) >= 2)
+
+		assert.Equal(t, frames[0].Function, "github.com/sttk/errs_test.newErrForStackTest")
+		assert.Equal(t, filepath.Base(frames[0].File), "stack_test.go")
+		assert.True(t, filepath.IsAbs(frames[0].File))
+		assert.Equal(t, frames[0].Line, 13)
+
+		assert.True(t, strings.HasPrefix(frames[1].Function, "github.com/sttk/errs_test.TestStackTrace"))
+		assert.Equal(t, frames[1].Line, 31)
+
+		assert.Equal(t, err.File(), "stack_test.go")
+		assert.Equal(t, err.Line(), 13)
+	})
+
+	t.Run("depth limits the number of frames", func(t *testing.T) {
+		errs.SetStackTraceDepth(1)
+		defer errs.SetStackTraceDepth(0)
+
+		err := newErrForStackTest()
+		frames := err.StackTrace()
+		assert.Len(t, frames, 1)
+		assert.Equal(t, frames[0].Function, "github.com/sttk/errs_test.newErrForStackTest")
+	})
+
+	t.Run("negative depth disables capturing", func(t *testing.T) {
+		errs.SetStackTraceDepth(-1)
+		defer errs.SetStackTraceDepth(0)
+		assert.Equal(t, errs.StackTraceDepth(), 0)
+
+		err := newErrForStackTest()
+		assert.Nil(t, err.StackTrace())
+	})
+
+	t.Run("too large depth is clamped", func(t *testing.T) {
+		errs.SetStackTraceDepth(1 << 31)
+		defer errs.SetStackTraceDepth(0)
+		assert.Equal(t, errs.StackTraceDepth(), errs.MaxStackTraceDepth)
+
+		errs.SetStackTraceDepth(errs.MaxStackTraceDepth + 1)
+		assert.Equal(t, errs.StackTraceDepth(), errs.MaxStackTraceDepth)
+	})
+
+	t.Run("ok has no stack trace", func(t *testing.T) {
+		errs.SetStackTraceDepth(32)
+		defer errs.SetStackTraceDepth(0)
+
+		assert.Nil(t, errs.Ok().StackTrace())
+	})
+}
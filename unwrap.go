@@ -0,0 +1,44 @@
+//go:build go1.20
+
+// Copyright (C) 2025-2026 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package errs
+
+// Unwrap returns all of the causes of the error, allowing errors.Is and errors.As to examine every
+// cause like an error created with errors.Join.
+//
+// NOTE: Since errors.Unwrap calls only the Unwrap method which returns an error, it returns nil for
+// an Err even if the Err has a cause. This is a breaking change from the earlier versions, so use
+// Cause to get the first cause instead.
+//
+// NOTE: On Go 1.18 and 1.19, this method returns only the first cause as an error, and the Is and
+// As methods examine the other causes instead.
+func (e Err) Unwrap() []error {
+	return e.Causes()
+}
+
+func (e Err) isInOtherCauses(target error) bool {
+	return false
+}
+
+func (e Err) asInOtherCauses(target any) bool {
+	return false
+}
+
+// Unwrap returns the Err(s) of all the failed items, allowing errors.Is and errors.As to examine
+// every failed item.
+//
+// NOTE: On Go 1.18 and 1.19, this method returns only the Err of the first failed item.
+func (b *BatchErr) Unwrap() []error {
+	items := b.Items()
+	if len(items) == 0 {
+		return nil
+	}
+	errs := make([]error, len(items))
+	for i, it := range items {
+		errs[i] = it.Err
+	}
+	return errs
+}
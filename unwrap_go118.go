@@ -0,0 +1,63 @@
+//go:build !go1.20
+
+// Copyright (C) 2025-2026 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package errs
+
+import (
+	"errors"
+	"reflect"
+)
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// Unwrap returns the first cause of the error, allowing it to be chained.
+// The other causes are examined by the Is and As methods, so errors.Is and errors.As examine
+// every cause.
+//
+// NOTE: On Go 1.20 or later, this method returns all of the causes as []error.
+func (e Err) Unwrap() error {
+	return e.cause
+}
+
+func (e Err) isInOtherCauses(target error) bool {
+	if e.causes == nil {
+		return false
+	}
+	for _, c := range (*e.causes)[1:] {
+		if errors.Is(c, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e Err) asInOtherCauses(target any) bool {
+	if e.causes == nil {
+		return false
+	}
+	// errors.As panics for a target which points to neither an interface nor an error.
+	t := reflect.TypeOf(target).Elem()
+	if t.Kind() != reflect.Interface && !t.Implements(errorType) {
+		return false
+	}
+	for _, c := range (*e.causes)[1:] {
+		if errors.As(c, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Unwrap returns the Err of the first failed item, or nil if no failed item is recorded.
+//
+// NOTE: On Go 1.20 or later, this method returns the Err(s) of all the failed items as []error.
+func (b *BatchErr) Unwrap() error {
+	items := b.Items()
+	if len(items) == 0 {
+		return nil
+	}
+	return items[0].Err
+}
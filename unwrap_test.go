@@ -0,0 +1,43 @@
+//go:build go1.20
+
+package errs_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/errs"
+)
+
+func TestErr_Unwrap(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		assert.Nil(t, errs.Ok().Unwrap())
+	})
+
+	t.Run("no cause", func(t *testing.T) {
+		assert.Nil(t, errs.New("abc").Unwrap())
+	})
+
+	t.Run("a cause", func(t *testing.T) {
+		cause := errors.New("def")
+		assert.Equal(t, errs.New("abc", cause).Unwrap(), []error{cause})
+	})
+
+	t.Run("multiple causes", func(t *testing.T) {
+		cause1 := errors.New("def")
+		cause2 := errors.New("ghi")
+		err := errs.New("abc", cause1, cause2)
+		assert.Equal(t, err.Unwrap(), []error{cause1, cause2})
+		assert.Nil(t, errors.Unwrap(err))
+	})
+
+	t.Run("errors.Unwrap returns nil even if there is a cause", func(t *testing.T) {
+		// errors.Unwrap calls only Unwrap() error, so use Cause to get the first cause.
+		cause := errors.New("def")
+		err := errs.New("abc", cause)
+		assert.Nil(t, errors.Unwrap(err))
+		assert.Equal(t, err.Cause(), cause)
+		assert.True(t, errors.Is(err, cause))
+	})
+}